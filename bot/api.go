@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+)
+
+// https://core.telegram.org/bots/api
+
+const defaultBaseURL = "https://api.telegram.org"
+
+type apiResponse struct {
+	Ok          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	ErrorCode   int             `json:"error_code,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+type Update struct {
+	UpdateID      int            `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+type Message struct {
+	MessageID int       `json:"message_id"`
+	From      *User     `json:"from,omitempty"`
+	Chat      Chat      `json:"chat"`
+	Text      string    `json:"text,omitempty"`
+	Caption   string    `json:"caption,omitempty"`
+	Document  *Document `json:"document,omitempty"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type Document struct {
+	FileID   string `json:"file_id"`
+	FileName string `json:"file_name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	FileSize int    `json:"file_size,omitempty"`
+}
+
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Message *Message `json:"message,omitempty"`
+	Data    string   `json:"data,omitempty"`
+}
+
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+type sendMessage struct {
+	ChatID      int64                 `json:"chat_id"`
+	Text        string                `json:"text"`
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+type answerCallbackQuery struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+}
+
+// ===========================================
+// call - POST JSON params to bot API method and decode result [PRIVATE]
+func (b *Bot) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.methodURL(method), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error during creation of request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return b.do(method, req, result)
+}
+
+// do - execute request and decode bot API envelope [PRIVATE]
+func (b *Bot) do(method string, req *http.Request, result interface{}) error {
+	resp, err := b.Http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error during read response body: %s", err)
+	}
+
+	if b.Debug {
+		b.logf("[DEBUG] %s: %s", method, string(data))
+	}
+
+	var r apiResponse
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return fmt.Errorf("error during decoding %s response: %s", method, err)
+	}
+
+	if !r.Ok {
+		return fmt.Errorf("%s failed: %d %s", method, r.ErrorCode, r.Description)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(r.Result, result)
+}
+
+func (b *Bot) methodURL(method string) string {
+	return b.BaseURL + "/bot" + b.Token + "/" + method
+}
+
+// ===========================================
+// API methods
+
+// GetUpdates long polls for updates newer than offset
+func (b *Bot) GetUpdates(ctx context.Context, offset, timeout int) ([]Update, error) {
+	var r []Update
+	err := b.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message", "callback_query"},
+	}, &r)
+
+	return r, err
+}
+
+// SendMessage sends text to chat, keyboard can be nil
+func (b *Bot) SendMessage(ctx context.Context, chatID int64, text string, keyboard *InlineKeyboardMarkup) error {
+	return b.call(ctx, "sendMessage", sendMessage{
+		ChatID:      chatID,
+		Text:        text,
+		ReplyMarkup: keyboard,
+	}, nil)
+}
+
+// SendDocument uploads content as file with given name to chat
+func (b *Bot) SendDocument(ctx context.Context, chatID int64, name string, content io.Reader) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	err := w.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	if err != nil {
+		return err
+	}
+
+	part, err := w.CreateFormFile("document", name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, content)
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", b.methodURL("sendDocument"), &buf)
+	if err != nil {
+		return fmt.Errorf("error during creation of request: %s", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	return b.do("sendDocument", req, nil)
+}
+
+// AnswerCallback stops the loading indicator on the pressed inline button
+func (b *Bot) AnswerCallback(ctx context.Context, ID, text string) error {
+	return b.call(ctx, "answerCallbackQuery", answerCallbackQuery{
+		CallbackQueryID: ID,
+		Text:            text,
+	}, nil)
+}
+
+// buttonData - callback data for inline button
+func buttonData(command string, ID int) string {
+	return command + ":" + strconv.Itoa(ID)
+}
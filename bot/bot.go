@@ -0,0 +1,430 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/0x0bsod/torrBot"
+	"github.com/0x0bsod/torrBot/client"
+	"github.com/0x0bsod/torrBot/torrent"
+)
+
+// Telegram limit for message text
+const maxMessageLen = 4096
+
+// long polling timeout in seconds
+const pollTimeout = 30
+
+// limit for handling one update, daemon calls included
+const updateTimeout = time.Minute
+
+// Parameters for new bot
+type Parameters struct {
+	Token        string
+	BaseURL      string // bot API URL, https://api.telegram.org if empty
+	AllowedChats []int64
+	Debug        bool
+	Transmission *t.Transmission
+}
+
+// Bot - Telegram front-end for transmission daemon
+type Bot struct {
+	Token        string
+	BaseURL      string
+	AllowedChats map[int64]bool
+	Debug        bool
+	Http         *http.Client
+	Transmission *t.Transmission
+	Logger       *log.Logger
+
+	offset int
+}
+
+// NewBot return bot instance, no network calls are made
+// only AllowedChats can use the bot, at least one is required
+func NewBot(p Parameters) (*Bot, error) {
+	if p.Token == "" {
+		return nil, fmt.Errorf("bot token is required")
+	}
+	if p.Transmission == nil {
+		return nil, fmt.Errorf("transmission client is required")
+	}
+	if len(p.AllowedChats) == 0 {
+		return nil, fmt.Errorf("at least one allowed chat is required")
+	}
+
+	b := &Bot{
+		Token:        p.Token,
+		BaseURL:      strings.TrimRight(p.BaseURL, "/"),
+		AllowedChats: map[int64]bool{},
+		Debug:        p.Debug,
+		Http:         &http.Client{Timeout: (pollTimeout + 10) * time.Second},
+		Transmission: p.Transmission,
+	}
+	if b.BaseURL == "" {
+		b.BaseURL = defaultBaseURL
+	}
+	for _, i := range p.AllowedChats {
+		b.AllowedChats[i] = true
+	}
+
+	return b, nil
+}
+
+// Run long polls for updates and handle them until ctx is done
+func (b *Bot) Run(ctx context.Context) error {
+	for {
+		updates, err := b.GetUpdates(ctx, b.offset, pollTimeout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			b.logf("[ERROR] %s", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			b.offset = u.UpdateID + 1
+			b.HandleUpdate(ctx, u)
+		}
+	}
+}
+
+// HandleUpdate dispatch one update to command handlers
+// handling is limited by updateTimeout, so hung daemon doesn't block the bot
+func (b *Bot) HandleUpdate(ctx context.Context, u Update) {
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	switch {
+	case u.Message != nil:
+		if !b.allowed(u.Message.Chat.ID) {
+			return
+		}
+		b.handleMessage(ctx, u.Message)
+	case u.CallbackQuery != nil && u.CallbackQuery.Message != nil:
+		if !b.allowed(u.CallbackQuery.Message.Chat.ID) {
+			return
+		}
+		b.handleCallback(ctx, u.CallbackQuery)
+	}
+}
+
+func (b *Bot) allowed(chatID int64) bool {
+	return b.AllowedChats[chatID]
+}
+
+func (b *Bot) handleMessage(ctx context.Context, m *Message) {
+	fields := strings.Fields(m.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		b.ingest(ctx, m)
+		return
+	}
+
+	// "/list@SomeBot" in group chats
+	command := strings.SplitN(fields[0], "@", 2)[0]
+
+	text, keyboard, err := b.command(ctx, command, fields[1:])
+	if err != nil {
+		text = "Error: " + err.Error()
+	}
+
+	b.reply(ctx, m.Chat.ID, text, keyboard)
+}
+
+func (b *Bot) handleCallback(ctx context.Context, q *CallbackQuery) {
+	parts := strings.SplitN(q.Data, ":", 2)
+
+	text, keyboard, err := b.command(ctx, "/"+parts[0], parts[1:])
+	if err != nil {
+		text = "Error: " + err.Error()
+	}
+
+	err = b.AnswerCallback(ctx, q.ID, "")
+	if err != nil {
+		b.logf("[ERROR] %s", err)
+	}
+
+	b.reply(ctx, q.Message.Chat.ID, text, keyboard)
+}
+
+// reply send text, too long text is sent as file
+func (b *Bot) reply(ctx context.Context, chatID int64, text string, keyboard *InlineKeyboardMarkup) {
+	var err error
+	if len(text) > maxMessageLen {
+		err = b.SendDocument(ctx, chatID, "reply.txt", strings.NewReader(text))
+	} else {
+		err = b.SendMessage(ctx, chatID, text, keyboard)
+	}
+	if err != nil {
+		b.logf("[ERROR] %s", err)
+	}
+}
+
+func (b *Bot) logf(format string, v ...interface{}) {
+	if b.Logger != nil {
+		b.Logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+// =====================================================================================================================
+// Commands
+// =====================================================================================================================
+
+const help = `/list [status] [label:x] [sort:field] [page:n] - list torrents, e.g. /list downloading sort:eta page:2
+/add <magnet> - add magnet link
+/stop <id> - stop torrent
+/next <id> - download torrent next, move it to the queue top
+/remove <id> [data] - remove torrent, with local data if "data" given
+/stats - session statistics
+send .torrent file or text with magnet links to add them`
+
+func (b *Bot) command(ctx context.Context, command string, args []string) (string, *InlineKeyboardMarkup, error) {
+	switch command {
+	case "/start", "/help":
+		return help, nil, nil
+	case "/list":
+		return b.list(ctx, args)
+	case "/add":
+		links := ExtractMagnets(strings.Join(args, " "))
+		if len(links) == 0 {
+			return "", nil, fmt.Errorf("usage: /add <magnet>")
+		}
+		if len(links) == 1 {
+			text, err := b.add(ctx, links[0])
+			return text, nil, err
+		}
+		res := make([]string, 0, len(links))
+		for _, i := range links {
+			res = append(res, b.addLink(ctx, i))
+		}
+		return strings.Join(res, "\n"), nil, nil
+	case "/stop":
+		ID, err := argID(args)
+		if err != nil {
+			return "", nil, err
+		}
+		err = b.Transmission.WithContext(ctx).StopTorrents(client.SelectIDs(ID))
+		return fmt.Sprintf("Torrent %d stopped", ID), nil, err
+	case "/next":
+		ID, err := argID(args)
+		if err != nil {
+			return "", nil, err
+		}
+		_, err = b.Transmission.WithContext(ctx).Reorder([]int{ID})
+		return fmt.Sprintf("Torrent %d is next in queue", ID), nil, err
+	case "/remove":
+		ID, err := argID(args)
+		if err != nil {
+			return "", nil, err
+		}
+		rmLocalData := len(args) > 1 && args[1] == "data"
+		err = b.Transmission.WithContext(ctx).RemoveTorrents(client.SelectIDs(ID), rmLocalData)
+		return fmt.Sprintf("Torrent %d removed", ID), nil, err
+	case "/confirmremove":
+		ID, err := argID(args)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("Remove torrent %d?", ID), confirmRemove(ID), nil
+	case "/cancel":
+		return "Cancelled", nil, nil
+	case "/stats":
+		text, err := b.stats(ctx)
+		return text, nil, err
+	}
+
+	return "Unknown command\n" + help, nil, nil
+}
+
+func (b *Bot) list(ctx context.Context, args []string) (string, *InlineKeyboardMarkup, error) {
+	q, err := b.listQuery(ctx, args)
+	if err != nil {
+		return "", nil, err
+	}
+
+	torrents, err := q.Do()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(torrents) == 0 {
+		return "No torrents", nil, nil
+	}
+
+	var sb strings.Builder
+	keyboard := &InlineKeyboardMarkup{}
+	for _, i := range torrents {
+		sb.WriteString(formatTorrent(i))
+		sb.WriteString("\n")
+
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, []InlineKeyboardButton{
+			{Text: fmt.Sprintf("Stop %d", i.ID), CallbackData: buttonData("stop", i.ID)},
+			{Text: fmt.Sprintf("Remove %d", i.ID), CallbackData: buttonData("confirmremove", i.ID)},
+		})
+	}
+
+	return sb.String(), keyboard, nil
+}
+
+// statuses by /list argument
+var listStatuses = map[string][]int{
+	"stopped":     {torrent.StatusStopped},
+	"checking":    {torrent.StatusCheckWait, torrent.StatusCheck},
+	"queued":      {torrent.StatusCheckWait, torrent.StatusDownloadWait, torrent.StatusSeedWait},
+	"downloading": {torrent.StatusDownload},
+	"seeding":     {torrent.StatusSeed},
+}
+
+// sort keys by /list argument, other names are torrent-get keys
+var listSorts = map[string]torrent.GetField{
+	"ratio":    torrent.UploadRatio,
+	"size":     torrent.TotalSize,
+	"progress": torrent.PercentDone,
+	"added":    torrent.AddedDate,
+	"queue":    torrent.QueuePosition,
+}
+
+// torrents per /list page
+const listPageSize = 20
+
+// listQuery build query from "/list downloading label:tv sort:-eta page:2" arguments, first page by default
+func (b *Bot) listQuery(ctx context.Context, args []string) (*t.Query, error) {
+	q := b.Transmission.WithContext(ctx).Query(torrent.ID, torrent.Name, torrent.Status, torrent.PercentDone, torrent.UploadRatio)
+
+	var statuses []int
+	page := 1
+	for _, i := range args {
+		switch {
+		case strings.HasPrefix(i, "page:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(i, "page:"))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("bad page: %s", strings.TrimPrefix(i, "page:"))
+			}
+			page = n
+		case strings.HasPrefix(i, "label:"):
+			q.Label(strings.TrimPrefix(i, "label:"))
+		case strings.HasPrefix(i, "sort:"):
+			key := strings.TrimPrefix(i, "sort:")
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+
+			f, ok := listSorts[key]
+			if !ok {
+				f, ok = torrent.ParseField(key)
+			}
+			if !ok {
+				return nil, fmt.Errorf("unknown sort field: %s", key)
+			}
+			q.SortBy(f, desc)
+		default:
+			s, ok := listStatuses[i]
+			if !ok {
+				return nil, fmt.Errorf("unknown status: %s", i)
+			}
+			statuses = append(statuses, s...)
+		}
+	}
+	if len(statuses) > 0 {
+		q.Status(statuses...)
+	}
+	q.Page((page-1)*listPageSize, listPageSize)
+
+	return q, nil
+}
+
+func (b *Bot) add(ctx context.Context, magnetLink string) (string, error) {
+	r, err := b.Transmission.WithContext(ctx).AddMagnet(magnetLink, t.AddOptions{})
+	return b.addResult(ctx, r, err)
+}
+
+// addResult describe torrent-add outcome, duplicate is not an error for user
+func (b *Bot) addResult(ctx context.Context, r t.AddResult, err error) (string, error) {
+	var dup *t.DuplicateError
+	if errors.As(err, &dup) {
+		return b.duplicate(ctx, dup.Torrent), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Added %d: %s", r.ID, r.Name), nil
+}
+
+func (b *Bot) duplicate(ctx context.Context, d t.AddedTorrent) string {
+	text := fmt.Sprintf("Already downloading %d: %s", d.ID, d.Name)
+
+	torrents, err := b.Transmission.WithContext(ctx).ByIDFields(d.ID, torrent.PercentDone)
+	if err == nil && len(torrents) > 0 {
+		text += fmt.Sprintf(", %.0f%% done", torrents[0].PercentDone*100)
+	}
+
+	return text
+}
+
+func (b *Bot) stats(ctx context.Context) (string, error) {
+	s, err := b.Transmission.WithContext(ctx).SessionStats()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Torrents: %d (active %d, paused %d)\nDown: %s/s\nUp: %s/s",
+		s.TorrentCount, s.ActiveTorrentCount, s.PausedTorrentCount,
+		formatBytes(s.DownloadSpeed), formatBytes(s.UploadSpeed)), nil
+}
+
+// =====================================================================================================================
+// Helpers
+// =====================================================================================================================
+
+// confirmRemove - keyboard asking to confirm removal from /list button
+func confirmRemove(ID int) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+		{Text: fmt.Sprintf("Yes, remove %d", ID), CallbackData: buttonData("remove", ID)},
+		{Text: "Cancel", CallbackData: buttonData("cancel", ID)},
+	}}}
+}
+
+func argID(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, fmt.Errorf("torrent id required")
+	}
+
+	ID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("bad torrent id: %s", args[0])
+	}
+
+	return ID, nil
+}
+
+func formatTorrent(i *torrent.Torrent) string {
+	return fmt.Sprintf("%d. %s\n%s, %.1f%%, ratio %.2f", i.ID, i.Name, i.StatusString, i.PercentDone*100, i.UploadRatio)
+}
+
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := unit, 0
+	for i := n / unit; i >= unit; i /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	t "github.com/0x0bsod/torrBot"
+)
+
+// .torrent files are small, anything bigger is not a torrent
+const maxTorrentSize = 10 << 20
+
+var magnetRe = regexp.MustCompile(`magnet:\?[^\s"'<>]+`)
+
+type File struct {
+	FileID   string `json:"file_id"`
+	FileSize int    `json:"file_size,omitempty"`
+	FilePath string `json:"file_path,omitempty"`
+}
+
+// GetFile resolves file ID to downloadable file path
+func (b *Bot) GetFile(ctx context.Context, fileID string) (File, error) {
+	var r File
+	err := b.call(ctx, "getFile", map[string]string{"file_id": fileID}, &r)
+
+	return r, err
+}
+
+// DownloadFile returns content of file attached to message
+func (b *Bot) DownloadFile(ctx context.Context, fileID string) ([]byte, error) {
+	f, err := b.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if f.FileSize > maxTorrentSize {
+		return nil, fmt.Errorf("file is too big: %d bytes", f.FileSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", b.BaseURL+"/file/bot"+b.Token+"/"+f.FilePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error during creation of request: %s", err)
+	}
+
+	resp, err := b.Http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error during file download: %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTorrentSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error during read file: %s", err)
+	}
+	if len(data) > maxTorrentSize {
+		return nil, fmt.Errorf("file is too big")
+	}
+
+	return data, nil
+}
+
+// ExtractMagnets returns every magnet link found in free-form text
+func ExtractMagnets(text string) []string {
+	return magnetRe.FindAllString(text, -1)
+}
+
+func isTorrentDocument(d *Document) bool {
+	return d.MimeType == "application/x-bittorrent" || strings.HasSuffix(strings.ToLower(d.FileName), ".torrent")
+}
+
+// ingest adds attached .torrent document and magnet links from message
+// reply is sent for every added item
+func (b *Bot) ingest(ctx context.Context, m *Message) {
+	if m.Document != nil && isTorrentDocument(m.Document) {
+		b.reply(ctx, m.Chat.ID, b.addDocument(ctx, m.Document), nil)
+	}
+
+	text := m.Text
+	if m.Caption != "" {
+		text += "\n" + m.Caption
+	}
+
+	for _, link := range ExtractMagnets(text) {
+		b.reply(ctx, m.Chat.ID, b.addLink(ctx, link), nil)
+	}
+}
+
+func (b *Bot) addDocument(ctx context.Context, d *Document) string {
+	data, err := b.DownloadFile(ctx, d.FileID)
+	if err != nil {
+		return fmt.Sprintf("%s: error: %s", d.FileName, err)
+	}
+
+	r, err := b.Transmission.WithContext(ctx).AddMetainfo(data, t.AddOptions{})
+	text, err := b.addResult(ctx, r, err)
+	if err != nil {
+		return fmt.Sprintf("%s: error: %s", d.FileName, err)
+	}
+
+	return text
+}
+
+func (b *Bot) addLink(ctx context.Context, link string) string {
+	text, err := b.add(ctx, link)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	return text
+}
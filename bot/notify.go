@@ -0,0 +1,24 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	t "github.com/0x0bsod/torrBot"
+)
+
+// NotifyGuard send disk guard events to allowed chats until events is closed
+func (b *Bot) NotifyGuard(ctx context.Context, events <-chan t.GuardEvent) {
+	for e := range events {
+		prefix := "Low disk space"
+		if e.Action == t.GuardResumed {
+			prefix = "Disk space is back"
+		}
+		text := fmt.Sprintf("%s: %s %d: %s, %s has %s free",
+			prefix, e.Action, e.Torrent.ID, e.Torrent.Name, e.DownloadDir, formatBytes(e.Free))
+
+		for chatID := range b.AllowedChats {
+			b.reply(ctx, chatID, text, nil)
+		}
+	}
+}
@@ -2,21 +2,40 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 )
 
 type Client struct {
 	Url      string
 	User     string
 	Password string
-	Token    string
+	Token    string // guarded by tokenMu once client is shared
 	Debug    bool
 	Http     *http.Client
+	Retry    *RetryPolicy // nil means no retries
+
+	UserAgent string
+	Logger    *log.Logger // debug output, standard logger if nil
+	Protocol  Protocol    // wire format, picked by Handshake if nil
+
+	tokenMu sync.Mutex
+	refresh *tokenRefresh
+
+	versionMu sync.Mutex
+	version   *Version
+	protocol  Protocol
+
+	lastID int32
 }
 
 // Parameters for new client
@@ -25,54 +44,78 @@ type Parameters struct {
 	User     string
 	Password string
 	Debug    bool
+	Retry    *RetryPolicy
+	TLS      TLSOptions
 }
 
 // ===========================================
 // Get - raw request, return []byte and error
-func (c *Client) get(endpoint string) ([]byte, error) {
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
 
-	resp, err := c.getResponse("GET", endpoint, nil)
+	resp, err := c.getResponse(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error during executing request: %s", err)
-	}
-
-	// update token
-	c.Token = resp.Header.Get("X-Transmission-Session-Id")
-
 	bodyByte, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error during read response body: %s", err)
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, &RPCError{StatusCode: resp.StatusCode, Body: bodyByte}
+	}
+
+	// update token
+	c.setToken(resp.Header.Get(tokenHeader))
+
 	return bodyByte, nil
 }
 
 // ===========================================
 // Post - raw request, return []byte and error
-func (c *Client) post(endpoint string, body []byte) ([]byte, error) {
+// 409 Conflict renews session id and retries, at most maxTokenRetries times
+func (c *Client) post(ctx context.Context, endpoint string, body []byte) ([]byte, error) {
 
-	resp, err := c.getResponse("POST", endpoint, body)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	for retry := 0; ; retry++ {
+		sent := c.token()
 
-	if resp.StatusCode == http.StatusConflict {
-		resp, err := c.getResponse("GET", "/", nil)
+		resp, err := c.getResponse(ctx, "POST", endpoint, body)
 		if err != nil {
-			return nil, fmt.Errorf("error during getting token: %s", err)
+			return nil, err
 		}
-		c.Token = resp.Header.Get("X-Transmission-Session-Id")
 
-		// try again
-		return c.post(endpoint, body)
-	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("error during post request: %s", resp.Status)
+		if resp.StatusCode != http.StatusConflict {
+			return c.readPost(resp)
+		}
+
+		bodyByte, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if retry >= maxTokenRetries {
+			return nil, &RPCError{StatusCode: resp.StatusCode, Body: bodyByte}
+		}
+
+		// new session id comes with 409, fetch it only if it is missing
+		if token := resp.Header.Get(tokenHeader); token != "" {
+			c.setToken(token)
+			continue
+		}
+
+		err = c.refreshToken(ctx, sent)
+		if err != nil {
+			return nil, fmt.Errorf("error during getting token: %w", err)
+		}
+	}
+}
+
+// readPost - read post response body [PRIVATE]
+func (c *Client) readPost(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyByte, _ := ioutil.ReadAll(resp.Body)
+		return nil, &RPCError{StatusCode: resp.StatusCode, Body: bodyByte}
 	}
 
 	bodyByte, err := ioutil.ReadAll(resp.Body)
@@ -85,7 +128,8 @@ func (c *Client) post(endpoint string, body []byte) ([]byte, error) {
 
 // ===========================================
 // getResponse return http.Response and error [PRIVATE]
-func (c *Client) getResponse(method, endpoint string, body []byte) (*http.Response, error) {
+// request is bound to ctx, so cancellation and deadline abort it
+func (c *Client) getResponse(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
 	urlStr := c.Url + endpoint
 
 	// check full URL
@@ -100,7 +144,7 @@ func (c *Client) getResponse(method, endpoint string, body []byte) (*http.Respon
 		bodyReader = bytes.NewReader(body)
 	}
 
-	req, err := http.NewRequest(method, _url.String(), bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, _url.String(), bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("error during creation of request: %s", err)
 	}
@@ -111,7 +155,10 @@ func (c *Client) getResponse(method, endpoint string, body []byte) (*http.Respon
 	}
 
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("X-Transmission-Session-Id", c.Token)
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	req.Header.Add(tokenHeader, c.token())
 
 	resp, err := c.Http.Do(req)
 	if err != nil {
@@ -137,33 +184,117 @@ type Request struct {
 }
 
 type ReqArguments struct {
-	Fields            []string `json:"fields,omitempty"`
-	IDs               []int    `json:"ids,omitempty"`
-	FileName          string   `json:"filename,omitempty"`
-	DownloadDir       string   `json:"download-dir,omitempty"`
-	MetaInfo          string   `json:"metainfo,omitempty"`
-	Paused            bool     `json:"paused,omitempty"`
-	PeerLimit         int      `json:"peer-limit,omitempty"`
-	BandwidthPriority int      `json:"bandwidth-priority,omitempty"`
-	FilesWanted       []string `json:"files-wanted,omitempty"`
-	FilesUnwanted     []string `json:"files-unwanted,omitempty"`
-	PriorityHigh      []int    `json:"priority-high,omitempty"`
-	PriorityLow       []int    `json:"priority-low,omitempty"`
-	PriorityNormal    []int    `json:"priority-normal,omitempty"`
-	DeleteLocalData   bool     `json:"delete-local-data"`
-	Path              string   `json:"path"`
+	Fields          []string         `json:"fields,omitempty"`
+	IDs             *TorrentSelector `json:"ids,omitempty"`
+	FileName        string           `json:"filename,omitempty"`
+	Cookies         string           `json:"cookies,omitempty"`
+	DownloadDir     string           `json:"download-dir,omitempty"`
+	MetaInfo        string           `json:"metainfo,omitempty"`
+	Paused          *bool            `json:"paused,omitempty"`
+	PeerLimit       int              `json:"peer-limit,omitempty"`
+	FilesWanted     []int            `json:"files-wanted,omitempty"`
+	FilesUnwanted   []int            `json:"files-unwanted,omitempty"`
+	PriorityHigh    []int            `json:"priority-high,omitempty"`
+	PriorityLow     []int            `json:"priority-low,omitempty"`
+	PriorityNormal  []int            `json:"priority-normal,omitempty"`
+	DeleteLocalData bool             `json:"delete-local-data"`
+	Path            string           `json:"path"`
+	Name            string           `json:"name,omitempty"`
+	Move            bool             `json:"move,omitempty"`
+	*TorrentSettings
+
+	// session-set arguments, merged into the same object on encode
+	// embedding it next to TorrentSettings would drop keys both have, e.g. seedRatioLimit
+	Session *SessionSettings `json:"-"`
+}
+
+// reqArguments - ReqArguments without MarshalJSON [PRIVATE]
+type reqArguments ReqArguments
+
+func (a ReqArguments) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(reqArguments(a))
+	if err != nil || a.Session == nil {
+		return data, err
+	}
+
+	session, err := json.Marshal(a.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	var tmp map[string]json.RawMessage
+	err = json.Unmarshal(data, &tmp)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(session, &tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(tmp)
 }
 
 func (c *Client) ApiCall(p *Request) ([]byte, error) {
-	b, err := json.Marshal(p)
+	return c.ApiCallCtx(context.Background(), p)
+}
+
+// ApiCallCtx - ApiCall bound to ctx, token refresh and retries use the same ctx
+func (c *Client) ApiCallCtx(ctx context.Context, p *Request) ([]byte, error) {
+	err := c.checkVersion(p)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	proto := c.wireProtocol()
+	b, err := proto.Encode(p, int(atomic.AddInt32(&c.lastID, 1)))
 	if err != nil {
 		return []byte{}, err
 	}
 
-	data, err := c.post("/", b)
+	var data []byte
+	for attempt := 0; ; attempt++ {
+		data, err = c.post(ctx, "/", b)
+		if err == nil || !c.Retry.shouldRetry(p.Method, attempt, err) {
+			break
+		}
+
+		if c.Debug {
+			c.Logf("[DEBUG] %s attempt %d failed: %s", p.Method, attempt+1, err)
+		}
+		if sleep(ctx, c.Retry.delay(attempt)) != nil {
+			break
+		}
+	}
 	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			rpcErr.Method = p.Method
+			rpcErr.Tag = p.Tag
+		}
 		return []byte{}, err
 	}
 
+	data, err = proto.Decode(p, data)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error during decoding %s response: %s", proto.Name(), err)
+	}
+
+	// result other than "success" is an error too
+	var res Response
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error during decoding response: %s", err)
+	}
+	if res.Result != "success" {
+		return []byte{}, &RPCError{
+			Method:     p.Method,
+			Tag:        p.Tag,
+			Result:     res.Result,
+			StatusCode: http.StatusOK,
+			Body:       data,
+		}
+	}
+
 	return data, nil
 }
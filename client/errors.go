@@ -0,0 +1,71 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error classes, matched by errors.Is on *RPCError
+var (
+	ErrAuth            = errors.New("authentication failed")
+	ErrConflict        = errors.New("session id conflict")
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrServer          = errors.New("server error")
+)
+
+// RPCError - failed RPC call, either HTTP error or result other than "success"
+type RPCError struct {
+	Method     string
+	Tag        int
+	Result     string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *RPCError) Error() string {
+	method := e.Method
+	if method == "" {
+		method = "request"
+	}
+
+	if e.StatusCode != 0 && e.StatusCode != http.StatusOK {
+		return fmt.Sprintf("%s failed: %d %s", method, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return fmt.Sprintf("%s failed: %s", method, e.Result)
+}
+
+func (e *RPCError) Is(target error) bool {
+	return target != nil && e.Class() == target
+}
+
+// Class return one of Err* classes or nil if error is not classified
+func (e *RPCError) Class() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrAuth
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusBadRequest:
+		return ErrInvalidArgument
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+
+	result := strings.ToLower(e.Result)
+	switch {
+	case strings.Contains(result, "not found") || strings.Contains(result, "no such"):
+		return ErrNotFound
+	case strings.Contains(result, "invalid") || strings.Contains(result, "corrupt") ||
+		strings.Contains(result, "unrecognized") || strings.Contains(result, "argument") ||
+		strings.Contains(result, "no filename"):
+		return ErrInvalidArgument
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// Every call step right away and then every interval until ctx is done
+// step errors are passed to onErr if it isn't nil and ctx isn't done yet
+func Every(ctx context.Context, interval time.Duration, onErr func(error), step func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := step()
+		if err != nil && onErr != nil && ctx.Err() == nil {
+			onErr(err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
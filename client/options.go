@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Option - New client setting
+type Option func(*options)
+
+type options struct {
+	url        string
+	user       string
+	password   string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	logger     *log.Logger
+	debug      bool
+	retry      *RetryPolicy
+	tls        TLSOptions
+	handshake  bool
+	protocol   Protocol
+}
+
+// WithURL - daemon RPC URL, e.g. http://host:9091/transmission/rpc
+func WithURL(url string) Option {
+	return func(o *options) { o.url = url }
+}
+
+func WithAuth(user, password string) Option {
+	return func(o *options) {
+		o.user = user
+		o.password = password
+	}
+}
+
+// WithHTTPClient - use hc as is, WithTransport and WithTLS are ignored
+func WithHTTPClient(hc *http.Client) Option {
+	return func(o *options) { o.httpClient = hc }
+}
+
+// WithTransport - custom RoundTripper, WithTLS is ignored
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) { o.transport = rt }
+}
+
+// WithTimeout - limit for one HTTP request, long calls are better limited by context
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+func WithUserAgent(ua string) Option {
+	return func(o *options) { o.userAgent = ua }
+}
+
+// WithLogger - debug logger, enables debug output
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+		o.debug = true
+	}
+}
+
+func WithDebug(debug bool) Option {
+	return func(o *options) { o.debug = debug }
+}
+
+func WithRetry(p *RetryPolicy) Option {
+	return func(o *options) { o.retry = p }
+}
+
+func WithTLS(t TLSOptions) Option {
+	return func(o *options) { o.tls = t }
+}
+
+// WithHandshake - connect to daemon and negotiate version in New
+// otherwise first call connects and version stays unknown until Handshake
+func WithHandshake() Option {
+	return func(o *options) { o.handshake = true }
+}
+
+// WithProtocol - force wire format instead of picking it by Handshake
+func WithProtocol(p Protocol) Option {
+	return func(o *options) { o.protocol = p }
+}
+
+// New return client, no network calls are made unless WithHandshake is given
+func New(opts ...Option) (*Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.url == "" {
+		return nil, fmt.Errorf("daemon URL is required")
+	}
+
+	hc := o.httpClient
+	if hc == nil {
+		rt := o.transport
+		if rt == nil {
+			tr, err := NewTransport(o.tls)
+			if err != nil {
+				return nil, err
+			}
+			rt = tr
+		}
+		hc = &http.Client{Transport: rt}
+	}
+	if o.timeout > 0 {
+		tmp := *hc
+		tmp.Timeout = o.timeout
+		hc = &tmp
+	}
+
+	c := &Client{
+		Url:       o.url,
+		User:      o.user,
+		Password:  o.password,
+		Debug:     o.debug,
+		Http:      hc,
+		Retry:     o.retry,
+		UserAgent: o.userAgent,
+		Logger:    o.logger,
+		Protocol:  o.protocol,
+	}
+
+	if o.handshake {
+		_, err := c.Handshake(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error during handshake: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Options return options equal to p
+func (p Parameters) Options() []Option {
+	return []Option{
+		WithURL(p.Url),
+		WithAuth(p.User, p.Password),
+		WithDebug(p.Debug),
+		WithRetry(p.Retry),
+		WithTLS(p.TLS),
+	}
+}
+
+// Logf - debug output to Logger or standard logger
+func (c *Client) Logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
@@ -0,0 +1,274 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// first rpc-version speaking JSON-RPC 2.0 with snake_case keys
+const jsonRPCVersion = 18
+
+// Protocol - RPC wire format
+// callers always see legacy {result, arguments, tag} response with legacy keys
+type Protocol interface {
+	Name() string
+	// Encode request to wire format, id is used by protocols that need one
+	Encode(p *Request, id int) ([]byte, error)
+	// Decode wire response to p to legacy form
+	Decode(p *Request, data []byte) ([]byte, error)
+}
+
+var (
+	// Legacy - {method, arguments, tag} with hyphenated and camelCase keys
+	Legacy Protocol = legacyProtocol{}
+	// JSONRPC - JSON-RPC 2.0 with snake_case methods and keys
+	JSONRPC Protocol = jsonRPCProtocol{}
+)
+
+type legacyProtocol struct{}
+
+func (legacyProtocol) Name() string { return "legacy" }
+
+func (legacyProtocol) Encode(p *Request, id int) ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (legacyProtocol) Decode(p *Request, data []byte) ([]byte, error) {
+	return data, nil
+}
+
+type jsonRPCProtocol struct{}
+
+type jsonRPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+	ID      int         `json:"id"`
+}
+
+type jsonRPCResponse struct {
+	Result interface{} `json:"result"`
+	Error  *struct {
+		Code    int                    `json:"code"`
+		Message string                 `json:"message"`
+		Data    map[string]interface{} `json:"data,omitempty"`
+	} `json:"error,omitempty"`
+	ID int `json:"id"`
+}
+
+func (jsonRPCProtocol) Name() string { return "jsonrpc" }
+
+func (jsonRPCProtocol) Encode(p *Request, id int) ([]byte, error) {
+	args, err := json.Marshal(p.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := decodeAny(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonRPCRequest{
+		JSONRPC: "2.0",
+		Method:  toSnake(p.Method),
+		Params:  convertKeys(params, toSnake),
+		ID:      id,
+	})
+}
+
+func (jsonRPCProtocol) Decode(p *Request, data []byte) ([]byte, error) {
+	var res jsonRPCResponse
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	err := d.Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	legacy := Response{Result: "success", Tag: res.ID}
+	if res.Error != nil {
+		legacy.Result = res.Error.Message
+		if s, ok := res.Error.Data["error_string"].(string); ok && s != "" {
+			legacy.Result = fmt.Sprintf("%s: %s", res.Error.Message, s)
+		}
+	} else if args, ok := convertKeys(res.Result, methodKeys(p.Method)).(map[string]interface{}); ok {
+		legacy.Arguments = args
+	}
+
+	return json.Marshal(legacy)
+}
+
+// =====================================================================================================================
+// Key mapping
+// =====================================================================================================================
+
+// legacy response keys of each method by their snake_case form, for keys camelCase can't restore
+// same snake_case key may stand for different legacy keys in different methods,
+// e.g. download_dir is downloadDir in torrent-get and download-dir in session-get
+var (
+	keysMu sync.RWMutex
+	keys   = map[string]map[string]string{}
+)
+
+func init() {
+	RegisterStruct("session-get", Version{}, SessionSettings{})
+}
+
+// RegisterKeys add legacy keys of method response, so JSON-RPC responses use them
+func RegisterKeys(method string, legacy ...string) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+
+	if keys[method] == nil {
+		keys[method] = map[string]string{}
+	}
+	for _, i := range legacy {
+		keys[method][toSnake(i)] = i
+	}
+}
+
+// RegisterStruct add json keys of v and its nested structs to method response keys
+func RegisterStruct(method string, v ...interface{}) {
+	for _, i := range v {
+		registerType(method, reflect.TypeOf(i), map[reflect.Type]bool{})
+	}
+}
+
+func registerType(method string, t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name != "" {
+			RegisterKeys(method, name)
+		}
+		registerType(method, f.Type, seen)
+	}
+}
+
+// methodKeys return fromSnake bound to keys of method response [PRIVATE]
+func methodKeys(method string) func(string) string {
+	return func(s string) string {
+		return fromSnake(method, s)
+	}
+}
+
+// convertKeys return v with object keys renamed by fn
+// "fields" values are field names, so they are renamed too, "ids" only when it is "recently-active"
+func convertKeys(v interface{}, fn func(string) string) interface{} {
+	switch tmp := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(tmp))
+		for k, i := range tmp {
+			key := fn(k)
+			switch key {
+			case "fields":
+				res[key] = convertValues(i, fn)
+			case "ids":
+				res[key] = convertIDs(i, fn)
+			default:
+				res[key] = convertKeys(i, fn)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(tmp))
+		for n, i := range tmp {
+			res[n] = convertKeys(i, fn)
+		}
+		return res
+	}
+
+	return v
+}
+
+func convertValues(v interface{}, fn func(string) string) interface{} {
+	switch tmp := v.(type) {
+	case string:
+		return fn(tmp)
+	case []interface{}:
+		res := make([]interface{}, len(tmp))
+		for n, i := range tmp {
+			res[n] = convertValues(i, fn)
+		}
+		return res
+	}
+
+	return v
+}
+
+// convertIDs - rename "recently-active", numbers and hash strings are kept as is [PRIVATE]
+func convertIDs(v interface{}, fn func(string) string) interface{} {
+	if tmp, ok := v.(string); ok && (tmp == recentlyActive || tmp == toSnake(recentlyActive)) {
+		return fn(tmp)
+	}
+
+	return v
+}
+
+// toSnake convert "hashString", "download-dir" and "isUTP" to "hash_string", "download_dir" and "is_utp"
+func toSnake(s string) string {
+	r := []rune(s)
+	var sb strings.Builder
+
+	for i, c := range r {
+		switch {
+		case c == '-':
+			sb.WriteRune('_')
+		case unicode.IsUpper(c):
+			prevLower := i > 0 && (unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1]))
+			acronymEnd := i > 0 && unicode.IsUpper(r[i-1]) && i+1 < len(r) && unicode.IsLower(r[i+1])
+			if prevLower || acronymEnd {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(c))
+		default:
+			sb.WriteRune(c)
+		}
+	}
+
+	return sb.String()
+}
+
+// fromSnake return legacy key registered for method or camelCase form
+func fromSnake(method, s string) string {
+	keysMu.RLock()
+	k, ok := keys[method][s]
+	keysMu.RUnlock()
+	if ok {
+		return k
+	}
+
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+func decodeAny(data []byte) (interface{}, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	err := d.Decode(&v)
+	return v, err
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// torrent-get keys as torrent.Torrent tags them, client can't import torrent
+type testTorrent struct {
+	ID                int    `json:"id"`
+	DownloadDir       string `json:"downloadDir"`
+	BandwidthPriority int    `json:"bandwidthPriority"`
+	TotalSize         int    `json:"totalSize"`
+}
+
+type testTorrents struct {
+	Torrents []testTorrent `json:"torrents"`
+}
+
+// free-space reply keeps legacy total_size
+type testDiskSpace struct {
+	Path      string `json:"path"`
+	SizeBytes int    `json:"size-bytes"`
+	TotalSize int    `json:"total_size"`
+}
+
+func init() {
+	RegisterStruct("torrent-get", testTorrents{})
+	RegisterStruct("free-space", testDiskSpace{})
+}
+
+func decodeJSONRPC(t *testing.T, method, data string, v interface{}) {
+	t.Helper()
+
+	res, err := JSONRPC.Decode(&Request{Method: method}, []byte(data))
+	if err != nil {
+		t.Fatalf("%s: decode: %s", method, err)
+	}
+
+	var tmp struct {
+		Result    string          `json:"result"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	err = json.Unmarshal(res, &tmp)
+	if err != nil {
+		t.Fatalf("%s: %s", method, err)
+	}
+	if tmp.Result != "success" {
+		t.Fatalf("%s: result %q", method, tmp.Result)
+	}
+
+	err = json.Unmarshal(tmp.Arguments, v)
+	if err != nil {
+		t.Fatalf("%s: arguments %s: %s", method, tmp.Arguments, err)
+	}
+}
+
+func TestJSONRPCDecodeTorrentGet(t *testing.T) {
+	var r testTorrents
+	decodeJSONRPC(t, "torrent-get", `{"jsonrpc":"2.0","id":1,"result":{"torrents":[
+		{"id":7,"download_dir":"/data","bandwidth_priority":1,"total_size":1024}]}}`, &r)
+
+	want := testTorrent{ID: 7, DownloadDir: "/data", BandwidthPriority: 1, TotalSize: 1024}
+	if len(r.Torrents) != 1 || r.Torrents[0] != want {
+		t.Fatalf("got %+v, want %+v", r.Torrents, want)
+	}
+}
+
+func TestJSONRPCDecodeSessionGet(t *testing.T) {
+	var r struct {
+		Version
+		SessionSettings
+	}
+	decodeJSONRPC(t, "session-get", `{"jsonrpc":"2.0","id":2,"result":{
+		"download_dir":"/data","rpc_version":18,"rpc_version_minimum":14,"version":"4.1.0","seed_ratio_limit":2.5}}`, &r)
+
+	if r.DownloadDir == nil || *r.DownloadDir != "/data" {
+		t.Errorf("download-dir: got %v, want /data", r.DownloadDir)
+	}
+	if r.RPCVersion != 18 || r.RPCVersionMinimum != 14 || r.Version.Version != "4.1.0" {
+		t.Errorf("version: got %+v", r.Version)
+	}
+	if r.SeedRatioLimit == nil || *r.SeedRatioLimit != 2.5 {
+		t.Errorf("seedRatioLimit: got %v, want 2.5", r.SeedRatioLimit)
+	}
+}
+
+func TestJSONRPCDecodeFreeSpace(t *testing.T) {
+	var r testDiskSpace
+	decodeJSONRPC(t, "free-space", `{"jsonrpc":"2.0","id":3,"result":{
+		"path":"/data","size_bytes":10,"total_size":20}}`, &r)
+
+	want := testDiskSpace{Path: "/data", SizeBytes: 10, TotalSize: 20}
+	if r != want {
+		t.Fatalf("got %+v, want %+v", r, want)
+	}
+}
+
+func encodeJSONRPC(t *testing.T, p *Request) map[string]interface{} {
+	t.Helper()
+
+	data, err := JSONRPC.Encode(p, 1)
+	if err != nil {
+		t.Fatalf("%s: encode: %s", p.Method, err)
+	}
+
+	var req struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	err = json.Unmarshal(data, &req)
+	if err != nil {
+		t.Fatalf("%s: %s", p.Method, err)
+	}
+	if req.Method != toSnake(p.Method) {
+		t.Errorf("method: got %q, want %q", req.Method, toSnake(p.Method))
+	}
+
+	return req.Params
+}
+
+func TestJSONRPCEncodeIDs(t *testing.T) {
+	hash := "DB1327D2A23C11AEAB5B946B1A498FADF6422B49"
+	params := encodeJSONRPC(t, &Request{
+		Method: "torrent-get",
+		Arguments: ReqArguments{
+			Fields: []string{"id", "downloadDir", "hashString"},
+			IDs:    SelectHashes(hash, "c9a337562cb0360fd6f5ab40fd2b6b81c0b0d2cf"),
+		},
+	})
+
+	ids, _ := params["ids"].([]interface{})
+	if len(ids) != 2 || ids[0] != hash || ids[1] != "c9a337562cb0360fd6f5ab40fd2b6b81c0b0d2cf" {
+		t.Errorf("ids: got %v, want hashes as is", params["ids"])
+	}
+
+	fields, _ := params["fields"].([]interface{})
+	if len(fields) != 3 || fields[0] != "id" || fields[1] != "download_dir" || fields[2] != "hash_string" {
+		t.Errorf("fields: got %v", params["fields"])
+	}
+}
+
+func TestJSONRPCEncodeRecentlyActive(t *testing.T) {
+	params := encodeJSONRPC(t, &Request{
+		Method:    "torrent-get",
+		Arguments: ReqArguments{IDs: SelectRecentlyActive()},
+	})
+
+	if params["ids"] != "recently_active" {
+		t.Errorf("ids: got %v, want recently_active", params["ids"])
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy - retries of failed ApiCall
+// non-idempotent methods are retried only if request never reached the daemon
+type RetryPolicy struct {
+	MaxAttempts int           // attempts including the first one
+	BaseDelay   time.Duration // delay before first retry, doubled for next ones
+	MaxDelay    time.Duration // delay limit, no limit if zero
+	Jitter      float64       // random part of delay, 0..1
+	RetryOn     func(err error) bool
+}
+
+// DefaultRetryPolicy - 5 attempts within ~15 seconds, enough for daemon restart
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    8 * time.Second,
+		Jitter:      0.2,
+		RetryOn:     IsTransient,
+	}
+}
+
+// methods safe to repeat
+var idempotent = map[string]bool{
+	"torrent-get":   true,
+	"session-get":   true,
+	"session-stats": true,
+	"free-space":    true,
+	"port-test":     true,
+}
+
+func IsIdempotent(method string) bool {
+	return idempotent[method]
+}
+
+// IsTransient report timeouts, connection errors, server errors and 429/502/503/504 statuses
+// certificate, TLS and request setup errors are not transient
+func IsTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return errors.Is(rpcErr, ErrServer)
+	}
+
+	// http.Client wraps every failure into *url.Error, which is a net.Error too, classify what it wraps
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	if isTLSError(err) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isTLSError report certificate and handshake errors, repeating won't fix them [PRIVATE]
+func isTLSError(err error) bool {
+	var (
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+		rootsErr     x509.SystemRootsError
+		headerErr    tls.RecordHeaderError
+		opErr        *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &authorityErr), errors.As(err, &invalidErr), errors.As(err, &hostnameErr),
+		errors.As(err, &rootsErr), errors.As(err, &headerErr):
+		return true
+	case errors.As(err, &opErr) && opErr.Op == "remote error":
+		// tls alert sent by daemon
+		return true
+	}
+
+	return strings.HasPrefix(err.Error(), "tls: ") || strings.HasPrefix(err.Error(), "x509: ")
+}
+
+// notSent report that request failed on connect, so it is safe to repeat any method
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry - whether failed attempt (0-based) of method is repeated [PRIVATE]
+func (p *RetryPolicy) shouldRetry(method string, attempt int, err error) bool {
+	if p == nil || attempt+1 >= p.MaxAttempts {
+		return false
+	}
+
+	retryOn := p.RetryOn
+	if retryOn == nil {
+		retryOn = IsTransient
+	}
+	if !retryOn(err) {
+		return false
+	}
+
+	return IsIdempotent(method) || notSent(err)
+}
+
+// delay before retry after failed attempt (0-based)
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d += time.Duration((jitter()*2 - 1) * p.Jitter * float64(d))
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return d
+}
+
+var (
+	rndMu sync.Mutex
+	rnd   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// jitter return random number in [0, 1)
+func jitter() float64 {
+	rndMu.Lock()
+	defer rndMu.Unlock()
+
+	return rnd.Float64()
+}
+
+// sleep wait d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
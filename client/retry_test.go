@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// timeoutError - net.Error reporting timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func urlError(err error) error {
+	return &url.Error{Op: "Post", URL: "http://127.0.0.1:9091/transmission/rpc", Err: err}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", urlError(timeoutError{}), true},
+		{"refused", urlError(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), true},
+		{"reset", urlError(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+		{"eof", urlError(io.EOF), true},
+		{"unexpected eof", urlError(io.ErrUnexpectedEOF), true},
+		{"tls alert", urlError(&net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}), false},
+		{"scheme", urlError(errors.New(`unsupported protocol scheme "ftp"`)), false},
+		{"canceled", urlError(context.Canceled), false},
+		{"unavailable", &RPCError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"bad request", &RPCError{StatusCode: http.StatusBadRequest}, false},
+	}
+
+	for _, i := range tests {
+		if got := IsTransient(i.err); got != i.want {
+			t.Errorf("%s: IsTransient(%v) = %v, want %v", i.name, i.err, got, i.want)
+		}
+	}
+}
+
+func TestIsTransientHTTPClient(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// certificate of test server isn't trusted by default client
+	_, err := http.Get(srv.URL)
+	if err == nil || IsTransient(err) {
+		t.Errorf("untrusted certificate: IsTransient(%v) = true, want false", err)
+	}
+
+	_, err = http.Get("ftp://" + srv.Listener.Addr().String())
+	if err == nil || IsTransient(err) {
+		t.Errorf("unsupported scheme: IsTransient(%v) = true, want false", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err = http.Get("http://" + addr)
+	if err == nil || !IsTransient(err) {
+		t.Errorf("connection refused: IsTransient(%v) = false, want true", err)
+	}
+}
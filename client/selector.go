@@ -0,0 +1,49 @@
+package client
+
+import "encoding/json"
+
+const recentlyActive = "recently-active"
+
+// TorrentSelector - "ids" argument of torrent-* methods
+// nil selector means all torrents, so "ids" is omitted
+type TorrentSelector struct {
+	IDs            []int
+	Hashes         []string
+	RecentlyActive bool
+}
+
+// SelectIDs select torrents by IDs
+func SelectIDs(IDs ...int) *TorrentSelector {
+	return &TorrentSelector{IDs: IDs}
+}
+
+// SelectHashes select torrents by SHA1 hash strings
+func SelectHashes(hashes ...string) *TorrentSelector {
+	return &TorrentSelector{Hashes: hashes}
+}
+
+// SelectAll select all torrents
+func SelectAll() *TorrentSelector {
+	return nil
+}
+
+// SelectRecentlyActive select torrents active since last call
+func SelectRecentlyActive() *TorrentSelector {
+	return &TorrentSelector{RecentlyActive: true}
+}
+
+func (s *TorrentSelector) MarshalJSON() ([]byte, error) {
+	if s.RecentlyActive {
+		return json.Marshal(recentlyActive)
+	}
+
+	tmp := make([]interface{}, 0, len(s.IDs)+len(s.Hashes))
+	for _, i := range s.IDs {
+		tmp = append(tmp, i)
+	}
+	for _, i := range s.Hashes {
+		tmp = append(tmp, i)
+	}
+
+	return json.Marshal(tmp)
+}
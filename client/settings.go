@@ -0,0 +1,118 @@
+package client
+
+import "encoding/json"
+
+// Seed ratio / idle modes for torrent-set
+const (
+	SeedModeGlobal int = iota
+	SeedModeSingle
+	SeedModeUnlimited
+)
+
+// TorrentSettings - torrent-set mutators, nil fields are not sent
+type TorrentSettings struct {
+	BandwidthPriority   *int            `json:"bandwidthPriority,omitempty"`
+	DownloadLimit       *int            `json:"downloadLimit,omitempty"`
+	DownloadLimited     *bool           `json:"downloadLimited,omitempty"`
+	HonorsSessionLimits *bool           `json:"honorsSessionLimits,omitempty"`
+	Labels              *[]string       `json:"labels,omitempty"`
+	Location            *string         `json:"location,omitempty"`
+	QueuePosition       *int            `json:"queuePosition,omitempty"`
+	SeedIdleLimit       *int            `json:"seedIdleLimit,omitempty"`
+	SeedIdleMode        *int            `json:"seedIdleMode,omitempty"`
+	SeedRatioLimit      *float64        `json:"seedRatioLimit,omitempty"`
+	SeedRatioMode       *int            `json:"seedRatioMode,omitempty"`
+	TrackerAdd          []string        `json:"trackerAdd,omitempty"`
+	TrackerRemove       []int           `json:"trackerRemove,omitempty"`
+	TrackerReplace      TrackerReplaces `json:"trackerReplace,omitempty"`
+	UploadLimit         *int            `json:"uploadLimit,omitempty"`
+	UploadLimited       *bool           `json:"uploadLimited,omitempty"`
+}
+
+// TrackerReplace - new announce URL for tracker with ID
+type TrackerReplace struct {
+	ID       int
+	Announce string
+}
+
+// TrackerReplaces encoded as flat [id, url, id, url...] array as rpc-spec wants
+type TrackerReplaces []TrackerReplace
+
+func (t TrackerReplaces) MarshalJSON() ([]byte, error) {
+	tmp := make([]interface{}, 0, len(t)*2)
+	for _, i := range t {
+		tmp = append(tmp, i.ID, i.Announce)
+	}
+	return json.Marshal(tmp)
+}
+
+// ===========================================
+// Helpers for optional fields
+
+func Int(v int) *int { return &v }
+
+func Bool(v bool) *bool { return &v }
+
+func Float(v float64) *float64 { return &v }
+
+func String(v string) *string { return &v }
+
+func Strings(v ...string) *[]string {
+	if v == nil {
+		v = []string{}
+	}
+	return &v
+}
+
+// Encryption modes for session-set
+const (
+	EncryptionRequired  = "required"
+	EncryptionPreferred = "preferred"
+	EncryptionTolerated = "tolerated"
+)
+
+// SessionSettings - session-set mutators, nil fields are not sent
+type SessionSettings struct {
+	AltSpeedDown              *int     `json:"alt-speed-down,omitempty"`
+	AltSpeedEnabled           *bool    `json:"alt-speed-enabled,omitempty"`
+	AltSpeedTimeBegin         *int     `json:"alt-speed-time-begin,omitempty"`
+	AltSpeedTimeDay           *int     `json:"alt-speed-time-day,omitempty"`
+	AltSpeedTimeEnabled       *bool    `json:"alt-speed-time-enabled,omitempty"`
+	AltSpeedTimeEnd           *int     `json:"alt-speed-time-end,omitempty"`
+	AltSpeedUp                *int     `json:"alt-speed-up,omitempty"`
+	BlocklistEnabled          *bool    `json:"blocklist-enabled,omitempty"`
+	BlocklistURL              *string  `json:"blocklist-url,omitempty"`
+	CacheSizeMb               *int     `json:"cache-size-mb,omitempty"`
+	DhtEnabled                *bool    `json:"dht-enabled,omitempty"`
+	DownloadDir               *string  `json:"download-dir,omitempty"`
+	DownloadQueueEnabled      *bool    `json:"download-queue-enabled,omitempty"`
+	DownloadQueueSize         *int     `json:"download-queue-size,omitempty"`
+	Encryption                *string  `json:"encryption,omitempty"`
+	IdleSeedingLimit          *int     `json:"idle-seeding-limit,omitempty"`
+	IdleSeedingLimitEnabled   *bool    `json:"idle-seeding-limit-enabled,omitempty"`
+	IncompleteDir             *string  `json:"incomplete-dir,omitempty"`
+	IncompleteDirEnabled      *bool    `json:"incomplete-dir-enabled,omitempty"`
+	LpdEnabled                *bool    `json:"lpd-enabled,omitempty"`
+	PeerLimitGlobal           *int     `json:"peer-limit-global,omitempty"`
+	PeerLimitPerTorrent       *int     `json:"peer-limit-per-torrent,omitempty"`
+	PeerPort                  *int     `json:"peer-port,omitempty"`
+	PeerPortRandomOnStart     *bool    `json:"peer-port-random-on-start,omitempty"`
+	PexEnabled                *bool    `json:"pex-enabled,omitempty"`
+	PortForwardingEnabled     *bool    `json:"port-forwarding-enabled,omitempty"`
+	QueueStalledEnabled       *bool    `json:"queue-stalled-enabled,omitempty"`
+	QueueStalledMinutes       *int     `json:"queue-stalled-minutes,omitempty"`
+	RenamePartialFiles        *bool    `json:"rename-partial-files,omitempty"`
+	ScriptTorrentDoneEnabled  *bool    `json:"script-torrent-done-enabled,omitempty"`
+	ScriptTorrentDoneFilename *string  `json:"script-torrent-done-filename,omitempty"`
+	SeedQueueEnabled          *bool    `json:"seed-queue-enabled,omitempty"`
+	SeedQueueSize             *int     `json:"seed-queue-size,omitempty"`
+	SeedRatioLimit            *float64 `json:"seedRatioLimit,omitempty"`
+	SeedRatioLimited          *bool    `json:"seedRatioLimited,omitempty"`
+	SpeedLimitDown            *int     `json:"speed-limit-down,omitempty"`
+	SpeedLimitDownEnabled     *bool    `json:"speed-limit-down-enabled,omitempty"`
+	SpeedLimitUp              *int     `json:"speed-limit-up,omitempty"`
+	SpeedLimitUpEnabled       *bool    `json:"speed-limit-up-enabled,omitempty"`
+	StartAddedTorrents        *bool    `json:"start-added-torrents,omitempty"`
+	TrashOriginalTorrentFiles *bool    `json:"trash-original-torrent-files,omitempty"`
+	UtpEnabled                *bool    `json:"utp-enabled,omitempty"`
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// TLSOptions - TLS settings of daemon connection
+// certificate is verified against system roots unless Insecure is set
+type TLSOptions struct {
+	CAFile   string // PEM bundle added to system roots
+	CAPEM    []byte
+	CertFile string // client certificate and key for mutual TLS
+	KeyFile  string
+	CertPEM  []byte
+	KeyPEM   []byte
+	Insecure bool // skip verification, only for self-signed daemons on trusted network
+}
+
+// Config return tls.Config for options
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: o.Insecure,
+	}
+
+	caPEM := o.CAPEM
+	if o.CAFile != "" {
+		data, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error during reading CA bundle: %s", err)
+		}
+		caPEM = append(append([]byte{}, caPEM...), data...)
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+
+	var cert tls.Certificate
+	var err error
+	switch {
+	case o.CertFile != "" || o.KeyFile != "":
+		cert, err = tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	case len(o.CertPEM) > 0 || len(o.KeyPEM) > 0:
+		cert, err = tls.X509KeyPair(o.CertPEM, o.KeyPEM)
+	default:
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error during loading client certificate: %s", err)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+
+	return cfg, nil
+}
+
+// NewTransport return transport for daemon connection with TLS options applied
+func NewTransport(o TLSOptions) (*http.Transport, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy:              http.ProxyFromEnvironment,
+		MaxIdleConns:       10,
+		IdleConnTimeout:    30 * time.Second,
+		DisableCompression: true,
+		TLSClientConfig:    cfg,
+	}, nil
+}
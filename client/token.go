@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+)
+
+const tokenHeader = "X-Transmission-Session-Id"
+
+// limit of session id renewals for one request
+const maxTokenRetries = 3
+
+// tokenRefresh - in-flight session id request, shared by all waiting callers
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+func (c *Client) token() string {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	return c.Token
+}
+
+func (c *Client) setToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	c.Token = token
+}
+
+// refreshToken fetch new session id if current one is still stale
+// concurrent callers wait for the single request in flight
+func (c *Client) refreshToken(ctx context.Context, stale string) error {
+	c.tokenMu.Lock()
+	if c.Token != stale {
+		// already renewed by someone else
+		c.tokenMu.Unlock()
+		return nil
+	}
+
+	if r := c.refresh; r != nil {
+		c.tokenMu.Unlock()
+		select {
+		case <-r.done:
+			return r.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	r := &tokenRefresh{done: make(chan struct{})}
+	c.refresh = r
+	c.tokenMu.Unlock()
+
+	token, err := c.fetchToken(ctx)
+
+	c.tokenMu.Lock()
+	if err == nil {
+		c.Token = token
+	}
+	r.err = err
+	c.refresh = nil
+	c.tokenMu.Unlock()
+	close(r.done)
+
+	return err
+}
+
+// fetchToken - session id from GET response headers [PRIVATE]
+func (c *Client) fetchToken(ctx context.Context) (string, error) {
+	resp, err := c.getResponse(ctx, "GET", "/", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	token := resp.Header.Get(tokenHeader)
+	if token == "" {
+		bodyByte, _ := ioutil.ReadAll(resp.Body)
+		return "", &RPCError{StatusCode: resp.StatusCode, Body: bodyByte}
+	}
+
+	return token, nil
+}
+
+// RefreshToken fetch new session id from daemon
+func (c *Client) RefreshToken(ctx context.Context) error {
+	return c.refreshToken(ctx, c.token())
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported - method or argument is newer than daemon rpc-version
+var ErrUnsupported = errors.New("unsupported by daemon")
+
+// Version - daemon versions negotiated by Handshake
+type Version struct {
+	RPCVersion        int    `json:"rpc-version"`
+	RPCVersionMinimum int    `json:"rpc-version-minimum"`
+	RPCVersionSemver  string `json:"rpc-version-semver,omitempty"`
+	Version           string `json:"version"`
+}
+
+// rpc-version which introduced the method, rpc-spec.txt
+var methodVersions = map[string]int{
+	"torrent-reannounce":   5,
+	"blocklist-update":     5,
+	"port-test":            5,
+	"torrent-set-location": 6,
+	"torrent-start-now":    14,
+	"queue-move-top":       14,
+	"queue-move-up":        14,
+	"queue-move-down":      14,
+	"queue-move-bottom":    14,
+	"session-close":        14,
+	"free-space":           15,
+	"torrent-rename-path":  15,
+	"group-get":            17,
+	"group-set":            17,
+}
+
+// Handshake fetch daemon versions with session-get and store them on client
+// token is negotiated on the way
+func (c *Client) Handshake(ctx context.Context) (Version, error) {
+	data, err := c.ApiCallCtx(ctx, &Request{
+		Method: "session-get",
+		Arguments: ReqArguments{
+			Fields: []string{"rpc-version", "rpc-version-minimum", "rpc-version-semver", "version"},
+		},
+	})
+	if err != nil {
+		return Version{}, err
+	}
+
+	var res struct {
+		Arguments Version `json:"arguments"`
+	}
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return Version{}, fmt.Errorf("error during decoding session-get: %s", err)
+	}
+
+	c.versionMu.Lock()
+	v := res.Arguments
+	c.version = &v
+	c.protocol = Legacy
+	if v.RPCVersion >= jsonRPCVersion {
+		c.protocol = JSONRPC
+	}
+	c.versionMu.Unlock()
+
+	return v, nil
+}
+
+// Ping check daemon is reachable and negotiate version
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Handshake(ctx)
+	return err
+}
+
+// Version return negotiated version, false before Handshake
+func (c *Client) Version() (Version, bool) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.version == nil {
+		return Version{}, false
+	}
+	return *c.version, true
+}
+
+// AtLeast report daemon rpc-version is v or newer
+// true before Handshake, nothing is known then
+func (c *Client) AtLeast(v int) bool {
+	version, ok := c.Version()
+	return !ok || version.RPCVersion >= v
+}
+
+// Supports report daemon knows the method
+func (c *Client) Supports(method string) bool {
+	return c.AtLeast(methodVersions[method])
+}
+
+// wireProtocol return Protocol if set, else one picked by Handshake, Legacy before it [PRIVATE]
+func (c *Client) wireProtocol() Protocol {
+	if c.Protocol != nil {
+		return c.Protocol
+	}
+
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.protocol == nil {
+		return Legacy
+	}
+	return c.protocol
+}
+
+// checkVersion - refuse request needing newer daemon [PRIVATE]
+func (c *Client) checkVersion(p *Request) error {
+	version, ok := c.Version()
+	if !ok {
+		return nil
+	}
+
+	need := methodVersions[p.Method]
+	if s := p.Arguments.TorrentSettings; s != nil && s.Labels != nil {
+		labels := 16
+		if p.Method == "torrent-add" {
+			labels = 17
+		}
+		if labels > need {
+			need = labels
+		}
+	}
+
+	if version.RPCVersion < need {
+		return fmt.Errorf("%w: %s needs rpc-version %d, daemon has %d", ErrUnsupported, p.Method, need, version.RPCVersion)
+	}
+
+	return nil
+}
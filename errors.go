@@ -0,0 +1,39 @@
+package transmissionRPC
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicate - torrent is already added, matched by errors.Is
+var ErrDuplicate = errors.New("duplicate torrent")
+
+// DuplicateError - torrent-add found the torrent already added
+type DuplicateError struct {
+	Torrent AddedTorrent
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate torrent %d: %s (%s)", e.Torrent.ID, e.Torrent.Name, e.Torrent.HashString)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// ErrNoSpace - not enough free space for torrent, matched by errors.Is
+var ErrNoSpace = errors.New("not enough free space")
+
+// NoSpaceError - CheckSpace found torrent doesn't fit
+type NoSpaceError struct {
+	Check SpaceCheck
+}
+
+func (e *NoSpaceError) Error() string {
+	return fmt.Sprintf("not enough free space for torrent %d: %s needs %d bytes, %s has %d free, %d reserved",
+		e.Check.ID, e.Check.Name, e.Check.Needed, e.Check.DownloadDir, e.Check.Free, e.Check.Reserve)
+}
+
+func (e *NoSpaceError) Is(target error) bool {
+	return target == ErrNoSpace
+}
@@ -0,0 +1,281 @@
+package transmissionRPC
+
+import (
+	"context"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+type GuardAction int
+
+const (
+	GuardStopped GuardAction = iota
+	GuardResumed
+)
+
+func (a GuardAction) String() string {
+	_strings := []string{
+		"stopped",
+		"resumed",
+	}
+
+	if a < GuardStopped || a > GuardResumed {
+		return ""
+	}
+
+	return _strings[a]
+}
+
+// GuardEvent - torrent stopped or resumed by DiskGuard, Free is free bytes in DownloadDir at that moment
+type GuardEvent struct {
+	Action      GuardAction
+	Torrent     *Torrent
+	DownloadDir string
+	Free        int
+}
+
+// fields DiskGuard reads
+var guardFields = []GetField{ID, Name, Status, DownloadDir, LeftUntilDone, BandwidthPriority, QueuePosition}
+
+// DiskGuard - stops downloads when free space in their download dir drops below Low
+// highest priority downloads whose left bytes fit into free space together are kept, the rest are stopped
+// torrents it stopped are resumed once free space reaches High, only those that fit above Low
+type DiskGuard struct {
+	Low  int
+	High int // Low if less than it
+
+	t      *Transmission
+	mu     sync.Mutex
+	paused map[int]bool
+}
+
+// NewDiskGuard return guard with watermarks in bytes, nothing is checked until Check or Run
+func (t *Transmission) NewDiskGuard(low, high int) *DiskGuard {
+	return &DiskGuard{
+		Low:    low,
+		High:   high,
+		t:      t,
+		paused: map[int]bool{},
+	}
+}
+
+// Check free space once, stop or resume torrents and return what was done
+// directories are checked independently, first error is returned after all of them
+func (g *DiskGuard) Check() ([]GuardEvent, error) {
+	return g.check(g.t)
+}
+
+// Run check every interval and send events to returned channel
+// channel is closed when ctx is done, check errors passed to onErr if it isn't nil
+func (g *DiskGuard) Run(ctx context.Context, interval time.Duration, onErr func(error)) <-chan GuardEvent {
+	ch := make(chan GuardEvent, 64)
+	t := g.t.WithContext(ctx)
+
+	go func() {
+		defer close(ch)
+
+		Every(ctx, interval, onErr, func() error {
+			events, err := g.check(t)
+			for _, e := range events {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return err
+		})
+	}()
+
+	return ch
+}
+
+// Paused return IDs of torrents stopped by guard and not resumed yet
+func (g *DiskGuard) Paused() []int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tmp := make([]int, 0, len(g.paused))
+	for ID := range g.paused {
+		tmp = append(tmp, ID)
+	}
+	sort.Ints(tmp)
+
+	return tmp
+}
+
+func (g *DiskGuard) check(t *Transmission) ([]GuardEvent, error) {
+	torrents, err := t.Query(guardFields...).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// forget torrents removed or started by user
+	byID := make(map[int]*Torrent, len(torrents))
+	for _, i := range torrents {
+		byID[i.ID] = i
+	}
+	for ID := range g.paused {
+		if i, ok := byID[ID]; !ok || i.Status != StatusStopped {
+			delete(g.paused, ID)
+		}
+	}
+
+	active := map[string][]*Torrent{}
+	paused := map[string][]*Torrent{}
+	for _, i := range torrents {
+		dir := path.Clean(i.DownloadDir)
+		switch {
+		case g.paused[i.ID]:
+			paused[dir] = append(paused[dir], i)
+		case i.Status == StatusDownload || i.Status == StatusDownloadWait:
+			active[dir] = append(active[dir], i)
+		}
+	}
+
+	dirs := make([]string, 0, len(active)+len(paused))
+	for dir := range active {
+		dirs = append(dirs, dir)
+	}
+	for dir := range paused {
+		if _, ok := active[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+
+	var events []GuardEvent
+	var firstErr error
+	var info *Info
+	for _, dir := range dirs {
+		free, err := g.free(t, dir, &info)
+		if err == nil {
+			var tmp []GuardEvent
+			switch {
+			case free < g.Low && len(active[dir]) > 0:
+				tmp, err = g.stop(t, dir, free, active[dir])
+			case free >= g.high() && len(paused[dir]) > 0:
+				tmp, err = g.resume(t, dir, free, paused[dir])
+			}
+			events = append(events, tmp...)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return events, firstErr
+}
+
+// stop - keep highest priority downloads whose left bytes fit into free space together, stop the rest [PRIVATE]
+func (g *DiskGuard) stop(t *Transmission, dir string, free int, torrents []*Torrent) ([]GuardEvent, error) {
+	byPriority(torrents)
+
+	var stop []*Torrent
+	needed := 0
+	for n, i := range torrents {
+		needed += i.LeftUntilDone
+		if needed > free {
+			stop = torrents[n:]
+			break
+		}
+	}
+	if len(stop) == 0 {
+		return nil, nil
+	}
+
+	IDs := make([]int, 0, len(stop))
+	for _, i := range stop {
+		IDs = append(IDs, i.ID)
+	}
+	err := t.StopTorrents(SelectIDs(IDs...))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]GuardEvent, 0, len(stop))
+	for _, i := range stop {
+		g.paused[i.ID] = true
+		events = append(events, GuardEvent{Action: GuardStopped, Torrent: i, DownloadDir: dir, Free: free})
+	}
+
+	return events, nil
+}
+
+// resume - start paused torrents fitting above Low, highest priority first [PRIVATE]
+func (g *DiskGuard) resume(t *Transmission, dir string, free int, torrents []*Torrent) ([]GuardEvent, error) {
+	byPriority(torrents)
+
+	var start []*Torrent
+	needed := 0
+	for _, i := range torrents {
+		if free-needed-i.LeftUntilDone < g.Low {
+			break
+		}
+		needed += i.LeftUntilDone
+		start = append(start, i)
+	}
+	if len(start) == 0 {
+		return nil, nil
+	}
+
+	IDs := make([]int, 0, len(start))
+	for _, i := range start {
+		IDs = append(IDs, i.ID)
+	}
+	err := t.StartTorrents(SelectIDs(IDs...))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]GuardEvent, 0, len(start))
+	for _, i := range start {
+		delete(g.paused, i.ID)
+		events = append(events, GuardEvent{Action: GuardResumed, Torrent: i, DownloadDir: dir, Free: free})
+	}
+
+	return events, nil
+}
+
+// free - free-space for dir, session-get for default download dir on daemons without free-space [PRIVATE]
+func (g *DiskGuard) free(t *Transmission, dir string, info **Info) (int, error) {
+	space, err := t.FreeSpace(dir)
+	if err == nil {
+		return space.SizeBytes, nil
+	}
+
+	if *info == nil {
+		tmp, infoErr := t.SessionInfo()
+		if infoErr != nil {
+			return 0, err
+		}
+		*info = &tmp
+	}
+	if path.Clean((*info).DownloadDir) == dir {
+		return (*info).DownloadDirFreeSpace, nil
+	}
+
+	return 0, err
+}
+
+func (g *DiskGuard) high() int {
+	if g.High < g.Low {
+		return g.Low
+	}
+	return g.High
+}
+
+// byPriority - sort by bandwidth priority, then queue position [PRIVATE]
+func byPriority(torrents []*Torrent) {
+	sort.SliceStable(torrents, func(a, b int) bool {
+		if torrents[a].BandwidthPriority != torrents[b].BandwidthPriority {
+			return torrents[a].BandwidthPriority > torrents[b].BandwidthPriority
+		}
+		return torrents[a].QueuePosition < torrents[b].QueuePosition
+	})
+}
@@ -0,0 +1,125 @@
+package transmissionRPC
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+// nesting limit for bencoded values, real .torrent files use just a few levels
+const maxBencodeDepth = 64
+
+// validateMetainfo reads r to the end and checks it is a single bencoded dictionary
+func validateMetainfo(r io.Reader) error {
+	br := bufio.NewReader(r)
+
+	c, err := br.ReadByte()
+	if err != nil {
+		return fmt.Errorf("invalid metainfo: %s", err)
+	}
+	if c != 'd' {
+		return fmt.Errorf("invalid metainfo: not a bencoded dictionary")
+	}
+
+	err = skipBencode(br, c, 0)
+	if err != nil {
+		return fmt.Errorf("invalid metainfo: %s", err)
+	}
+
+	_, err = br.ReadByte()
+	if err != io.EOF {
+		if err != nil {
+			return fmt.Errorf("invalid metainfo: %s", err)
+		}
+		return fmt.Errorf("invalid metainfo: trailing data")
+	}
+
+	return nil
+}
+
+// skipBencode consume value started with c [PRIVATE]
+func skipBencode(br *bufio.Reader, c byte, depth int) error {
+	if depth > maxBencodeDepth {
+		return fmt.Errorf("too deep nesting")
+	}
+
+	switch {
+	case c == 'i':
+		num, err := readUntil(br, 'e')
+		if err != nil {
+			return err
+		}
+		_, err = strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad integer: %s", err)
+		}
+		return nil
+	case c == 'l' || c == 'd':
+		for i := 0; ; i++ {
+			next, err := readByte(br)
+			if err != nil {
+				return err
+			}
+			if next == 'e' {
+				if c == 'd' && i%2 == 1 {
+					return fmt.Errorf("dictionary key without value")
+				}
+				return nil
+			}
+			if c == 'd' && i%2 == 0 && (next < '0' || next > '9') {
+				return fmt.Errorf("dictionary key is not a string")
+			}
+
+			err = skipBencode(br, next, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	case c >= '0' && c <= '9':
+		tail, err := readUntil(br, ':')
+		if err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(string(c)+tail, 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad string length: %s", err)
+		}
+
+		_, err = io.CopyN(ioutil.Discard, br, n)
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	return fmt.Errorf("unexpected byte %q", c)
+}
+
+func readByte(br *bufio.Reader) (byte, error) {
+	c, err := br.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return c, err
+}
+
+// readUntil return bytes before delim, delim is consumed
+// only used for numbers, so the length is limited
+func readUntil(br *bufio.Reader, delim byte) (string, error) {
+	var tmp []byte
+	for {
+		c, err := readByte(br)
+		if err != nil {
+			return "", err
+		}
+		if c == delim {
+			return string(tmp), nil
+		}
+		if len(tmp) >= 20 {
+			return "", fmt.Errorf("number is too long")
+		}
+		tmp = append(tmp, c)
+	}
+}
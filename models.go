@@ -7,22 +7,84 @@ import (
 
 // Request ===============================
 
+// AddOptions - per-call torrent-add arguments
+// empty DownloadDir and nil Paused fall back to Transmission.DownloadDir and Transmission.Paused
+// nil Paused with Transmission.Paused false leaves it to daemon start-added-torrents setting
+type AddOptions struct {
+	Cookies           string
+	DownloadDir       string
+	Paused            *bool
+	PeerLimit         int
+	BandwidthPriority *int
+	FilesWanted       []int
+	FilesUnwanted     []int
+	PriorityHigh      []int
+	PriorityLow       []int
+	PriorityNormal    []int
+	Labels            []string
+}
+
 // Response ===============================
 
+// AddedTorrent - torrent reported by torrent-add
+type AddedTorrent struct {
+	HashString string `json:"hashString,omitempty"`
+	ID         int    `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+}
+
 type Added struct {
-	TorrentAdded struct {
-		HashString string `json:"hashString,omitempty"`
-		ID         int    `json:"id,omitempty"`
-		Name       string `json:"name,omitempty"`
-	} `json:"torrent-added"`
+	TorrentAdded AddedTorrent `json:"torrent-added"`
 }
 
 type Duplicate struct {
-	TorrentDuplicate struct {
-		HashString string `json:"hashString,omitempty"`
-		ID         int    `json:"id,omitempty"`
-		Name       string `json:"name,omitempty"`
-	} `json:"torrent-duplicate"`
+	TorrentDuplicate AddedTorrent `json:"torrent-duplicate"`
+}
+
+// AddResult - torrent-add result, Duplicate is true if the torrent already existed
+type AddResult struct {
+	AddedTorrent
+	Duplicate bool
+}
+
+// DiskSpace - free-space result, TotalSize is 0 before rpc-version 17
+type DiskSpace struct {
+	Path      string `json:"path"`
+	SizeBytes int    `json:"size-bytes"`
+	TotalSize int    `json:"total_size,omitempty"`
+}
+
+// SpaceCheck - torrent needs against free space in its download dir
+// Needed is LeftUntilDone, it is unknown until magnet metadata is fetched
+type SpaceCheck struct {
+	ID          int
+	Name        string
+	DownloadDir string
+	Needed      int
+	SizeKnown   bool
+	Free        int
+	Reserve     int
+}
+
+// Fits report torrent can be completed leaving Reserve bytes free
+// unknown size fits, there is nothing to compare
+func (c SpaceCheck) Fits() bool {
+	return !c.SizeKnown || c.Free-c.Needed >= c.Reserve
+}
+
+// Relocated - torrent moved by SetLocation, daemon moves data in background
+type Relocated struct {
+	ID   int
+	Name string
+	From string
+	To   string
+}
+
+// Renamed - torrent-rename-path result, Path is the old path and Name is the new one
+type Renamed struct {
+	ID   int    `json:"id"`
+	Path string `json:"path"`
+	Name string `json:"name"`
 }
 
 // Other ===============================
@@ -107,6 +169,15 @@ type Statistics struct {
 	UploadSpeed        int `json:"uploadSpeed"`
 }
 
+// JSON-RPC responses use snake_case keys, map them back to the tags above
+func init() {
+	RegisterStruct("session-get", Info{})
+	RegisterStruct("session-stats", Statistics{})
+	RegisterStruct("torrent-add", Added{}, Duplicate{})
+	RegisterStruct("free-space", DiskSpace{})
+	RegisterStruct("torrent-rename-path", Renamed{})
+}
+
 func PrettyPrint(v interface{}) (err error) {
 	b, err := json.MarshalIndent(v, "", "  ")
 	if err == nil {
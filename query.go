@@ -0,0 +1,277 @@
+package transmissionRPC
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fields fetched when Query has none selected
+var defaultQueryFields = []GetField{ID, Name, Status, PercentDone, Eta, UploadRatio, TotalSize}
+
+// Query - torrent-get with client-side filtering, sorting and paging
+// fields needed by filters and sorting are added to the request
+type Query struct {
+	t       *Transmission
+	fields  []GetField
+	ids     *TorrentSelector
+	filters []func(*Torrent) bool
+	need    []GetField
+	less    func(a, b *Torrent) bool
+	offset  int
+	limit   int
+	err     error
+}
+
+// Query return builder for torrent-get, f are fields to fetch
+func (t *Transmission) Query(f ...GetField) *Query {
+	return &Query{t: t, fields: f}
+}
+
+// Fields add fields to fetch
+func (q *Query) Fields(f ...GetField) *Query {
+	q.fields = append(q.fields, f...)
+	return q
+}
+
+// Select restrict query to selected torrents, nil means all
+func (q *Query) Select(IDs *TorrentSelector) *Query {
+	q.ids = IDs
+	return q
+}
+
+// ByIDs restrict query to torrents with these IDs
+func (q *Query) ByIDs(IDs ...int) *Query {
+	q.ids = SelectIDs(IDs...)
+	return q
+}
+
+// ByHashes restrict query to torrents with these hash strings
+func (q *Query) ByHashes(hashes ...string) *Query {
+	q.ids = SelectHashes(hashes...)
+	return q
+}
+
+// Where add filter, need are fields it reads
+func (q *Query) Where(f func(*Torrent) bool, need ...GetField) *Query {
+	q.filters = append(q.filters, f)
+	q.need = append(q.need, need...)
+	return q
+}
+
+// Status keep torrents in any of given statuses, StatusDownload etc.
+func (q *Query) Status(status ...int) *Query {
+	return q.Where(func(i *Torrent) bool {
+		for _, s := range status {
+			if i.Status == s {
+				return true
+			}
+		}
+		return false
+	}, Status)
+}
+
+// Label keep torrents having label, case insensitive
+func (q *Query) Label(label string) *Query {
+	return q.Where(func(i *Torrent) bool {
+		for _, l := range i.Labels {
+			if strings.EqualFold(l, label) {
+				return true
+			}
+		}
+		return false
+	}, Labels)
+}
+
+// TrackerHost keep torrents announcing to host or its subdomain
+func (q *Query) TrackerHost(host string) *Query {
+	host = strings.ToLower(host)
+	return q.Where(func(i *Torrent) bool {
+		for _, tr := range i.Trackers {
+			u, err := url.Parse(tr.Announce)
+			if err != nil {
+				continue
+			}
+			h := strings.ToLower(u.Hostname())
+			if h == host || strings.HasSuffix(h, "."+host) {
+				return true
+			}
+		}
+		return false
+	}, Trackers)
+}
+
+// DownloadDir keep torrents saved in dir or below it
+func (q *Query) DownloadDir(dir string) *Query {
+	dir = path.Clean(dir)
+	return q.Where(func(i *Torrent) bool {
+		d := path.Clean(i.DownloadDir)
+		return d == dir || strings.HasPrefix(d, strings.TrimSuffix(dir, "/")+"/")
+	}, DownloadDir)
+}
+
+// Ratio keep torrents with upload ratio in [min, max], max <= 0 means no upper bound
+func (q *Query) Ratio(min, max float64) *Query {
+	return q.Where(func(i *Torrent) bool {
+		return i.UploadRatio >= min && (max <= 0 || i.UploadRatio <= max)
+	}, UploadRatio)
+}
+
+// Size keep torrents with total size in [min, max] bytes, max <= 0 means no upper bound
+func (q *Query) Size(min, max int) *Query {
+	return q.Where(func(i *Torrent) bool {
+		return i.TotalSize >= min && (max <= 0 || i.TotalSize <= max)
+	}, TotalSize)
+}
+
+// Name keep torrents with name matching regular expression
+func (q *Query) Name(pattern string) *Query {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		q.err = fmt.Errorf("bad name pattern: %s", err)
+		return q
+	}
+
+	return q.Where(func(i *Torrent) bool {
+		return re.MatchString(i.Name)
+	}, Name)
+}
+
+// SortBy order result by field, unknown eta is sorted last
+func (q *Query) SortBy(f GetField, desc bool) *Query {
+	key, ok := sortKeys[f]
+	if !ok {
+		q.err = fmt.Errorf("can't sort by %s", f)
+		return q
+	}
+
+	q.need = append(q.need, f)
+	q.less = func(a, b *Torrent) bool {
+		if desc {
+			a, b = b, a
+		}
+		return key(a, b)
+	}
+	return q
+}
+
+// Page return at most limit torrents after skipping offset, limit <= 0 means all
+func (q *Query) Page(offset, limit int) *Query {
+	q.offset = offset
+	q.limit = limit
+	return q
+}
+
+// Do send torrent-get and apply filters, sorting and paging
+// fields newer than daemon rpc-version, filter and sort ones included, are refused
+func (q *Query) Do() ([]*Torrent, error) {
+	if q.err != nil {
+		return []*Torrent{}, q.err
+	}
+
+	fields := q.fieldList()
+	err := q.t.checkFields(fields)
+	if err != nil {
+		return []*Torrent{}, err
+	}
+
+	res, err := q.t.makeCall(&Request{
+		Method: "torrent-get",
+		Arguments: ReqArguments{
+			Fields: FieldList(fields...),
+			IDs:    q.ids,
+		},
+	})
+	if err != nil {
+		return []*Torrent{}, err
+	}
+
+	var r Torrents
+	err = q.t.extractArgs(res, &r)
+	if err != nil {
+		return []*Torrent{}, err
+	}
+	q.t.resolveStatus(r.Torrents)
+
+	tmp := make([]*Torrent, 0, len(r.Torrents))
+	for _, i := range r.Torrents {
+		if q.match(i) {
+			tmp = append(tmp, i)
+		}
+	}
+
+	if q.less != nil {
+		sort.SliceStable(tmp, func(a, b int) bool { return q.less(tmp[a], tmp[b]) })
+	}
+
+	if q.offset > 0 {
+		if q.offset >= len(tmp) {
+			return []*Torrent{}, nil
+		}
+		tmp = tmp[q.offset:]
+	}
+	if q.limit > 0 && q.limit < len(tmp) {
+		tmp = tmp[:q.limit]
+	}
+
+	return tmp, nil
+}
+
+// fieldList - selected fields plus ones filters need, without duplicates [PRIVATE]
+func (q *Query) fieldList() []GetField {
+	fields := q.fields
+	if len(fields) == 0 {
+		fields = defaultQueryFields
+	}
+
+	seen := map[GetField]bool{}
+	var tmp []GetField
+	for _, f := range append(append([]GetField{ID}, fields...), q.need...) {
+		if !seen[f] {
+			seen[f] = true
+			tmp = append(tmp, f)
+		}
+	}
+
+	return tmp
+}
+
+func (q *Query) match(i *Torrent) bool {
+	for _, f := range q.filters {
+		if !f(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKeys - less functions for sortable fields
+var sortKeys = map[GetField]func(a, b *Torrent) bool{
+	ID:            func(a, b *Torrent) bool { return a.ID < b.ID },
+	Name:          func(a, b *Torrent) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	Status:        func(a, b *Torrent) bool { return a.Status < b.Status },
+	Eta:           func(a, b *Torrent) bool { return etaKey(a.Eta) < etaKey(b.Eta) },
+	PercentDone:   func(a, b *Torrent) bool { return a.PercentDone < b.PercentDone },
+	RateDownload:  func(a, b *Torrent) bool { return a.RateDownload < b.RateDownload },
+	RateUpload:    func(a, b *Torrent) bool { return a.RateUpload < b.RateUpload },
+	UploadRatio:   func(a, b *Torrent) bool { return a.UploadRatio < b.UploadRatio },
+	TotalSize:     func(a, b *Torrent) bool { return a.TotalSize < b.TotalSize },
+	SizeWhenDone:  func(a, b *Torrent) bool { return a.SizeWhenDone < b.SizeWhenDone },
+	LeftUntilDone: func(a, b *Torrent) bool { return a.LeftUntilDone < b.LeftUntilDone },
+	AddedDate:     func(a, b *Torrent) bool { return a.AddedDate < b.AddedDate },
+	DoneDate:      func(a, b *Torrent) bool { return a.DoneDate < b.DoneDate },
+	ActivityDate:  func(a, b *Torrent) bool { return a.ActivityDate < b.ActivityDate },
+	QueuePosition: func(a, b *Torrent) bool { return a.QueuePosition < b.QueuePosition },
+	DownloadDir:   func(a, b *Torrent) bool { return a.DownloadDir < b.DownloadDir },
+}
+
+// etaKey - eta is -1 or -2 when unknown, put it after any known eta
+func etaKey(eta int) int {
+	if eta < 0 {
+		return int(^uint(0) >> 1)
+	}
+	return eta
+}
@@ -0,0 +1,184 @@
+package transmissionRPC
+
+import (
+	"fmt"
+	"sort"
+)
+
+// QueueMove - torrent-set queuePosition done by Reorder
+type QueueMove struct {
+	ID       int
+	Position int
+}
+
+func (t *Transmission) QueueTop(IDs *TorrentSelector) error {
+	return t.action("queue-move-top", ReqArguments{IDs: IDs})
+}
+
+func (t *Transmission) QueueUp(IDs *TorrentSelector) error {
+	return t.action("queue-move-up", ReqArguments{IDs: IDs})
+}
+
+func (t *Transmission) QueueDown(IDs *TorrentSelector) error {
+	return t.action("queue-move-down", ReqArguments{IDs: IDs})
+}
+
+func (t *Transmission) QueueBottom(IDs *TorrentSelector) error {
+	return t.action("queue-move-bottom", ReqArguments{IDs: IDs})
+}
+
+// SetQueuePosition move torrent to position, others are shifted
+func (t *Transmission) SetQueuePosition(ID, position int) error {
+	return t.SetTorrent(SelectIDs(ID), TorrentSettings{QueuePosition: &position})
+}
+
+// Queue return all torrents ordered by queue position
+func (t *Transmission) Queue() ([]*Torrent, error) {
+	return t.Query(ID, Name, Status, QueuePosition).SortBy(QueuePosition, false).Do()
+}
+
+// Reorder put torrents at the head of the queue in the order of IDs, others keep their order after them
+// only torrents out of place are moved, done moves are returned even on error
+func (t *Transmission) Reorder(IDs []int) ([]QueueMove, error) {
+	queue, err := t.Queue()
+	if err != nil {
+		return nil, err
+	}
+
+	cur := make([]int, 0, len(queue))
+	for _, i := range queue {
+		cur = append(cur, i.ID)
+	}
+
+	want, err := queueOrder(cur, IDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []QueueMove
+	for _, m := range queueMoves(cur, want) {
+		err := t.SetQueuePosition(m.ID, m.Position)
+		if err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// queueOrder - IDs first, then the rest of cur in its order [PRIVATE]
+func queueOrder(cur, IDs []int) ([]int, error) {
+	known := make(map[int]bool, len(cur))
+	for _, i := range cur {
+		known[i] = true
+	}
+
+	first := make(map[int]bool, len(IDs))
+	for _, i := range IDs {
+		if !known[i] {
+			return nil, fmt.Errorf("%w: no torrent %d in queue", ErrInvalidArgument, i)
+		}
+		if first[i] {
+			return nil, fmt.Errorf("%w: torrent %d listed twice", ErrInvalidArgument, i)
+		}
+		first[i] = true
+	}
+
+	want := append(make([]int, 0, len(cur)), IDs...)
+	for _, i := range cur {
+		if !first[i] {
+			want = append(want, i)
+		}
+	}
+
+	return want, nil
+}
+
+// queueMoves - minimal moves turning cur into want [PRIVATE]
+// torrents of the longest subsequence already in order stay, every other one is
+// put right after its predecessor in want, positions are simulated on a local copy
+func queueMoves(cur, want []int) []QueueMove {
+	pos := make(map[int]int, len(cur))
+	for n, i := range cur {
+		pos[i] = n
+	}
+
+	seq := make([]int, len(want))
+	for n, i := range want {
+		seq[n] = pos[i]
+	}
+	stay := map[int]bool{}
+	for _, n := range longestIncreasing(seq) {
+		stay[want[n]] = true
+	}
+
+	queue := append([]int(nil), cur...)
+	var moves []QueueMove
+	for n, i := range want {
+		if stay[i] {
+			continue
+		}
+
+		queue = removeID(queue, i)
+		target := 0
+		if n > 0 {
+			target = indexOf(queue, want[n-1]) + 1
+		}
+		queue = append(queue[:target], append([]int{i}, queue[target:]...)...)
+
+		moves = append(moves, QueueMove{ID: i, Position: target})
+	}
+
+	return moves
+}
+
+// longestIncreasing - indexes of one longest strictly increasing subsequence of seq [PRIVATE]
+func longestIncreasing(seq []int) []int {
+	var tails []int // index in seq of smallest tail for each length
+	prev := make([]int, len(seq))
+
+	for n, v := range seq {
+		l := sort.Search(len(tails), func(k int) bool { return seq[tails[k]] >= v })
+		if l > 0 {
+			prev[n] = tails[l-1]
+		} else {
+			prev[n] = -1
+		}
+		if l == len(tails) {
+			tails = append(tails, n)
+		} else {
+			tails[l] = n
+		}
+	}
+
+	res := make([]int, len(tails))
+	if len(tails) == 0 {
+		return res
+	}
+	for k, n := len(tails)-1, tails[len(tails)-1]; k >= 0; k-- {
+		res[k] = n
+		n = prev[n]
+	}
+
+	return res
+}
+
+func removeID(s []int, ID int) []int {
+	tmp := make([]int, 0, len(s))
+	for _, i := range s {
+		if i != ID {
+			tmp = append(tmp, i)
+		}
+	}
+	return tmp
+}
+
+func indexOf(s []int, ID int) int {
+	for n, i := range s {
+		if i == ID {
+			return n
+		}
+	}
+	return -1
+}
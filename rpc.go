@@ -2,21 +2,22 @@ package transmissionRPC
 
 import (
 	"bufio"
-	"crypto/tls"
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
+	"io"
 	"os"
-	"time"
+	"path"
+	"strings"
 )
 
 // https://github.com/transmission/transmission/blob/master/extras/rpc-spec.txt
 
 type Transmission struct {
 	http        *client
+	ctx         context.Context
 	DownloadDir string
 	Paused      bool
 	Debug       bool
@@ -24,31 +25,31 @@ type Transmission struct {
 
 // ===========================================
 // NewClient return client instance with token
+// daemon certificate is verified against system roots
 func NewClient(url, user, password string) (*Transmission, error) {
-	tr := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    30 * time.Second,
-		DisableCompression: true,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-	}
+	return NewWithOptions(WithURL(url), WithAuth(user, password), WithHandshake())
+}
 
-	client := client{
-		Url:      url,
-		User:     user,
-		Password: password,
-		Http:     &http.Client{Transport: tr},
-	}
+// NewClientTLS return client instance with token, TLS options applied
+func NewClientTLS(url, user, password string, o TLSOptions) (*Transmission, error) {
+	return NewWithOptions(WithURL(url), WithAuth(user, password), WithTLS(o), WithHandshake())
+}
 
-	resp, err := client.getResponse("GET", "/", nil)
+// NewWithOptions return client instance, no network calls are made unless WithHandshake is given
+func NewWithOptions(opts ...Option) (*Transmission, error) {
+	c, err := New(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error during getting token: %s", err)
+		return nil, err
 	}
 
-	client.Token = resp.Header.Get("X-Transmission-Session-Id")
+	return &Transmission{http: c, Debug: c.Debug}, nil
+}
 
-	return &Transmission{http: &client}, nil
+// WithContext return copy of t, its calls are bound to ctx
+func (t *Transmission) WithContext(ctx context.Context) *Transmission {
+	tmp := *t
+	tmp.ctx = ctx
+	return &tmp
 }
 
 // =====================================================================================================================
@@ -63,154 +64,151 @@ func (t *Transmission) ByID(ID int) ([]*Torrent, error) {
 				AddedDate, Peers, IsFinished, LeftUntilDone,
 				PercentDone, Eta, TotalSize, RateDownload,
 				RateUpload, UploadRatio, Files, FileStats),
-			IDs: []int{ID},
+			IDs: SelectIDs(ID),
 		},
 	})
 	if err != nil {
 		return []*Torrent{}, err
 	}
 
-	if res.Result == "success" {
-		var r Torrents
-		err := t.extractArgs(res, &r)
-		if err != nil {
-			return []*Torrent{}, err
-		}
-		if len(r.Torrents) == 0 {
-			return r.Torrents, fmt.Errorf("no torrents")
-		}
-		t.resolveStatus(r.Torrents)
-		return r.Torrents, nil
+	var r Torrents
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return []*Torrent{}, err
 	}
-
-	return []*Torrent{}, fmt.Errorf("request failed")
+	if len(r.Torrents) == 0 {
+		return r.Torrents, fmt.Errorf("no torrents")
+	}
+	t.resolveStatus(r.Torrents)
+	return r.Torrents, nil
 }
 
+// ByIDFields return torrent with given fields, fields newer than daemon are refused
 func (t *Transmission) ByIDFields(ID int, f ...GetField) ([]*Torrent, error) {
+	err := t.checkFields(f)
+	if err != nil {
+		return []*Torrent{}, err
+	}
+
 	res, err := t.makeCall(&Request{
 		Method: "torrent-get",
 		Arguments: ReqArguments{
 			Fields: FieldList(f...),
-			IDs:    []int{ID},
+			IDs:    SelectIDs(ID),
 		},
 	})
 	if err != nil {
 		return []*Torrent{}, err
 	}
 
-	if res.Result == "success" {
-		var r Torrents
-		err := t.extractArgs(res, &r)
-		if err != nil {
-			return []*Torrent{}, err
-		}
-		if len(r.Torrents) == 0 {
-			return r.Torrents, fmt.Errorf("no torrents")
-		}
-
-		t.resolveStatus(r.Torrents)
-
-		return r.Torrents, nil
+	var r Torrents
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return []*Torrent{}, err
+	}
+	if len(r.Torrents) == 0 {
+		return r.Torrents, fmt.Errorf("no torrents")
 	}
 
-	return []*Torrent{}, fmt.Errorf("request failed")
+	t.resolveStatus(r.Torrents)
+
+	return r.Torrents, nil
 }
 
 // =====================================================================================================================
 // Add
 // =====================================================================================================================
 
-func (t *Transmission) AddFile(path string) (Added, error) {
-	// open file and encode to base64
+func (t *Transmission) AddFile(path string, opts AddOptions) (AddResult, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return Added{}, err
+		return AddResult{}, err
 	}
 	defer f.Close()
 
-	reader := bufio.NewReader(f)
-	content, _ := ioutil.ReadAll(reader)
+	return t.AddReader(bufio.NewReader(f), opts)
+}
 
-	base64Str := base64.StdEncoding.EncodeToString(content)
-	// ===
+// AddMetainfo adds torrent from raw .torrent file content
+func (t *Transmission) AddMetainfo(content []byte, opts AddOptions) (AddResult, error) {
+	return t.AddReader(bytes.NewReader(content), opts)
+}
 
-	p := &Request{
-		Method: "torrent-add",
-		Arguments: ReqArguments{
-			MetaInfo: base64Str,
-			Paused:   t.Paused,
-		},
-	}
-	if t.DownloadDir != "" {
-		p.Arguments.DownloadDir = t.DownloadDir
+// AddReader adds torrent from .torrent file content read from r
+// content is validated and base64 encoded in one pass, without keeping raw copy
+// encoded string is built once, Builder.String doesn't copy it
+func (t *Transmission) AddReader(r io.Reader, opts AddOptions) (AddResult, error) {
+	var buf strings.Builder
+	enc := base64.NewEncoder(base64.StdEncoding, &buf)
+
+	err := validateMetainfo(io.TeeReader(r, enc))
+	if err != nil {
+		return AddResult{}, err
 	}
 
-	res, err := t.makeCall(p)
+	err = enc.Close()
 	if err != nil {
-		return Added{}, err
+		return AddResult{}, err
 	}
 
-	if res.Result == "success" {
-		var r Added
-		err := t.extractArgs(res, &r)
-		if err != nil {
-			return Added{}, err
-		}
+	return t.add(ReqArguments{MetaInfo: buf.String()}, opts)
+}
 
-		if (Added{}) == r {
-			var d Duplicate
-			err := t.extractArgs(res, &d)
-			if err != nil {
-				return Added{}, err
-			}
-			errB, _ := json.Marshal(d)
-			return Added{}, fmt.Errorf(string(errB))
-		}
+func (t *Transmission) AddMagnet(magnetLink string, opts AddOptions) (AddResult, error) {
+	return t.add(ReqArguments{FileName: magnetLink}, opts)
+}
 
-		return r, nil
+// add - torrent-add call with options applied [PRIVATE]
+// already existing torrent is returned with Duplicate set and *DuplicateError
+func (t *Transmission) add(args ReqArguments, opts AddOptions) (AddResult, error) {
+	// explicit false is sent too, otherwise daemon start-added-torrents decides
+	args.Paused = opts.Paused
+	if args.Paused == nil && t.Paused {
+		args.Paused = Bool(true)
+	}
+	args.DownloadDir = t.DownloadDir
+	if opts.DownloadDir != "" {
+		args.DownloadDir = opts.DownloadDir
 	}
 
-	return Added{}, fmt.Errorf("request failed")
-}
-
-func (t *Transmission) AddMagnet(magnetLink string) (Added, error) {
-	p := &Request{
-		Method: "torrent-add",
-		Arguments: ReqArguments{
-			FileName: magnetLink,
-			Paused:   t.Paused,
-		},
+	args.Cookies = opts.Cookies
+	args.PeerLimit = opts.PeerLimit
+	args.FilesWanted = opts.FilesWanted
+	args.FilesUnwanted = opts.FilesUnwanted
+	args.PriorityHigh = opts.PriorityHigh
+	args.PriorityLow = opts.PriorityLow
+	args.PriorityNormal = opts.PriorityNormal
+	if opts.BandwidthPriority != nil || opts.Labels != nil {
+		args.TorrentSettings = &TorrentSettings{BandwidthPriority: opts.BandwidthPriority}
+		if opts.Labels != nil {
+			args.TorrentSettings.Labels = &opts.Labels
+		}
 	}
-	if t.DownloadDir != "" {
-		p.Arguments.DownloadDir = t.DownloadDir
+
+	res, err := t.makeCall(&Request{
+		Method:    "torrent-add",
+		Arguments: args,
+	})
+	if err != nil {
+		return AddResult{}, err
 	}
 
-	res, err := t.makeCall(p)
+	var r Added
+	err = t.extractArgs(res, &r)
 	if err != nil {
-		return Added{}, err
+		return AddResult{}, err
 	}
 
-	if res.Result == "success" {
-		var r Added
-		err := t.extractArgs(res, &r)
+	if (Added{}) == r {
+		var d Duplicate
+		err = t.extractArgs(res, &d)
 		if err != nil {
-			return Added{}, err
-		}
-
-		if (Added{}) == r {
-			var d Duplicate
-			err := t.extractArgs(res, &d)
-			if err != nil {
-				return Added{}, err
-			}
-			errB, _ := json.Marshal(d)
-			return Added{}, fmt.Errorf(string(errB))
+			return AddResult{}, err
 		}
-
-		return r, nil
+		return AddResult{AddedTorrent: d.TorrentDuplicate, Duplicate: true}, &DuplicateError{d.TorrentDuplicate}
 	}
 
-	return Added{}, fmt.Errorf("request failed")
+	return AddResult{AddedTorrent: r.TorrentAdded}, nil
 }
 
 // =====================================================================================================================
@@ -247,7 +245,7 @@ func (t *Transmission) SetPriority(ID, fileID, level int) error {
 
 	p := &Request{
 		Method:    "torrent-set",
-		Arguments: ReqArguments{IDs: []int{ID}},
+		Arguments: ReqArguments{IDs: SelectIDs(ID)},
 	}
 
 	switch level {
@@ -261,25 +259,146 @@ func (t *Transmission) SetPriority(ID, fileID, level int) error {
 		p.Arguments.PriorityHigh = fileIDs
 		break
 	default:
-		return fmt.Errorf("request failed")
+		return fmt.Errorf("unknown priority level: %d", level)
 	}
 
-	res, err := t.makeCall(p)
+	_, err := t.makeCall(p)
 	if err != nil {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
+	return nil
+}
+
+// SetTorrent applies settings to selected torrents
+// only non-nil fields of TorrentSettings are sent to the daemon
+func (t *Transmission) SetTorrent(IDs *TorrentSelector, s TorrentSettings) error {
+	_, err := t.makeCall(&Request{
+		Method: "torrent-set",
+		Arguments: ReqArguments{
+			IDs:             IDs,
+			TorrentSettings: &s,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetLocation set download dir of selected torrents, data is moved there if move is true
+// otherwise the daemon looks for data in newDir
+func (t *Transmission) SetLocation(IDs *TorrentSelector, newDir string, move bool) ([]Relocated, error) {
+	if newDir == "" || !isAbs(newDir) {
+		return nil, fmt.Errorf("%w: location must be absolute path: %q", ErrInvalidArgument, newDir)
+	}
+
+	torrents, err := t.Query(ID, Name, DownloadDir).Select(IDs).Do()
+	if err != nil {
+		return nil, err
+	}
+	if len(torrents) == 0 {
+		return nil, fmt.Errorf("no torrents")
 	}
 
-	return fmt.Errorf("request failed")
+	_, err = t.makeCall(&Request{
+		Method: "torrent-set-location",
+		Arguments: ReqArguments{
+			IDs:             IDs,
+			Move:            move,
+			TorrentSettings: &TorrentSettings{Location: &newDir},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tmp := make([]Relocated, 0, len(torrents))
+	for _, i := range torrents {
+		tmp = append(tmp, Relocated{ID: i.ID, Name: i.Name, From: i.DownloadDir, To: newDir})
+	}
+
+	return tmp, nil
+}
+
+// RenamePath rename file or directory oldPath of torrent to newName
+// oldPath is relative to download dir as in Files, e.g. "Album/01.flac" or "Album"
+func (t *Transmission) RenamePath(ID int, oldPath, newName string) (Renamed, error) {
+	if newName == "" || newName == "." || newName == ".." || strings.ContainsAny(newName, `/\`) {
+		return Renamed{}, fmt.Errorf("%w: bad new name: %q", ErrInvalidArgument, newName)
+	}
+
+	torrents, err := t.ByIDFields(ID, Files)
+	if err != nil {
+		return Renamed{}, err
+	}
+	files := torrents[0].Files
+	if len(files) == 0 {
+		return Renamed{}, fmt.Errorf("%w: torrent %d has no metadata yet", ErrInvalidArgument, ID)
+	}
+
+	oldPath = strings.Trim(oldPath, "/")
+	target := path.Join(path.Dir(oldPath), newName)
+	found := false
+	for _, i := range files {
+		if i.Name == oldPath || strings.HasPrefix(i.Name, oldPath+"/") {
+			found = true
+		}
+		if target != oldPath && (i.Name == target || strings.HasPrefix(i.Name, target+"/")) {
+			return Renamed{}, fmt.Errorf("%w: %q already exists in torrent %d", ErrInvalidArgument, target, ID)
+		}
+	}
+	if !found {
+		return Renamed{}, fmt.Errorf("%w: no %q in torrent %d", ErrInvalidArgument, oldPath, ID)
+	}
+
+	res, err := t.makeCall(&Request{
+		Method: "torrent-rename-path",
+		Arguments: ReqArguments{
+			IDs:  SelectIDs(ID),
+			Path: oldPath,
+			Name: newName,
+		},
+	})
+	if err != nil {
+		return Renamed{}, err
+	}
+
+	var r Renamed
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return Renamed{}, err
+	}
+	return r, nil
+}
+
+func (t *Transmission) StartTorrents(IDs *TorrentSelector) error {
+	return t.action("torrent-start", ReqArguments{IDs: IDs})
+}
+
+func (t *Transmission) StopTorrents(IDs *TorrentSelector) error {
+	return t.action("torrent-stop", ReqArguments{IDs: IDs})
+}
+
+func (t *Transmission) RemoveTorrents(IDs *TorrentSelector, rmLocalData bool) error {
+	return t.action("torrent-remove", ReqArguments{IDs: IDs, DeleteLocalData: rmLocalData})
 }
 
 // =====================================================================================================================
 // Other
 // =====================================================================================================================
 
+// Handshake negotiate daemon rpc-version, calls newer than it are refused afterwards
+func (t *Transmission) Handshake() (Version, error) {
+	return t.http.Handshake(t.context())
+}
+
+// Ping check daemon is reachable, version is negotiated too
+func (t *Transmission) Ping() error {
+	return t.http.Ping(t.context())
+}
+
 func (t *Transmission) SessionStats() (Statistics, error) {
 	res, err := t.makeCall(&Request{
 		Method: "session-stats",
@@ -288,16 +407,12 @@ func (t *Transmission) SessionStats() (Statistics, error) {
 		return Statistics{}, err
 	}
 
-	if res.Result == "success" {
-		var r Statistics
-		err := t.extractArgs(res, &r)
-		if err != nil {
-			return Statistics{}, err
-		}
-		return r, err
+	var r Statistics
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return Statistics{}, err
 	}
-
-	return Statistics{}, fmt.Errorf("request failed")
+	return r, err
 }
 
 func (t *Transmission) SessionInfo() (Info, error) {
@@ -308,30 +423,142 @@ func (t *Transmission) SessionInfo() (Info, error) {
 		return Info{}, err
 	}
 
-	if res.Result == "success" {
-		var r Info
-		err := t.extractArgs(res, &r)
-		if err != nil {
-			return Info{}, err
-		}
-		return r, nil
+	var r Info
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return Info{}, err
+	}
+	return r, nil
+}
+
+// SessionSet applies settings to the daemon session
+// only non-nil fields of SessionSettings are sent to the daemon
+func (t *Transmission) SessionSet(s SessionSettings) error {
+	_, err := t.makeCall(&Request{
+		Method:    "session-set",
+		Arguments: ReqArguments{Session: &s},
+	})
+	if err != nil {
+		return err
 	}
 
-	return Info{}, fmt.Errorf("request failed")
+	return nil
 }
 
-func (t *Transmission) FreeSpace(path string) error {
+// FreeSpace return free bytes in path on daemon side, rpc-version 15
+func (t *Transmission) FreeSpace(path string) (DiskSpace, error) {
 	res, err := t.makeCall(&Request{
 		Method:    "free-space",
 		Arguments: ReqArguments{Path: path},
 	})
+	if err != nil {
+		return DiskSpace{}, err
+	}
+
+	var r DiskSpace
+	err = t.extractArgs(res, &r)
+	if err != nil {
+		return DiskSpace{}, err
+	}
+	return r, nil
+}
+
+// CheckSpace compare what torrent still needs with free space in its download dir
+// error is *NoSpaceError if less than reserve bytes would stay free
+func (t *Transmission) CheckSpace(ID int, reserve int) (SpaceCheck, error) {
+	torrents, err := t.ByIDFields(ID, Name, DownloadDir, SizeWhenDone, LeftUntilDone)
+	if err != nil {
+		return SpaceCheck{}, err
+	}
+	i := torrents[0]
+
+	space, err := t.FreeSpace(i.DownloadDir)
+	if err != nil {
+		return SpaceCheck{}, err
+	}
+
+	c := SpaceCheck{
+		ID:          ID,
+		Name:        i.Name,
+		DownloadDir: i.DownloadDir,
+		Needed:      i.LeftUntilDone,
+		SizeKnown:   i.SizeWhenDone > 0,
+		Free:        space.SizeBytes,
+		Reserve:     reserve,
+	}
+	if !c.Fits() {
+		return c, &NoSpaceError{Check: c}
+	}
+
+	return c, nil
+}
+
+// =====================================================================================================================
+// Private
+// =====================================================================================================================
+
+func (t *Transmission) context() context.Context {
+	if t.ctx != nil {
+		return t.ctx
+	}
+	return context.Background()
+}
+
+// makeCall - send request bound to t context and decode response [PRIVATE]
+func (t *Transmission) makeCall(r *Request) (*Response, error) {
+	data, err := t.http.ApiCallCtx(t.context(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Debug {
+		t.http.Logf("[DEBUG] %s", string(data))
+	}
+
+	var res Response
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// checkFields - refuse fields newer than daemon rpc-version, nothing is known before Handshake [PRIVATE]
+func (t *Transmission) checkFields(f []GetField) error {
+	v, _ := t.http.Version()
+	return CheckFields(v.RPCVersion, f...)
+}
+
+// isAbs - unix or windows absolute path, daemon OS is unknown [PRIVATE]
+func isAbs(p string) bool {
+	return strings.HasPrefix(p, "/") || len(p) > 2 && p[1] == ':' && (p[2] == '\\' || p[2] == '/')
+}
+
+// action - call method without result arguments [PRIVATE]
+func (t *Transmission) action(method string, args ReqArguments) error {
+	_, err := t.makeCall(&Request{
+		Method:    method,
+		Arguments: args,
+	})
 	if err != nil {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
+	return nil
+}
+
+func (t *Transmission) extractArgs(res *Response, result interface{}) error {
+	tmp, err := json.Marshal(res.Arguments)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("request failed")
+	return json.Unmarshal(tmp, result)
+}
+
+func (t *Transmission) resolveStatus(torrents []*Torrent) {
+	for _, i := range torrents {
+		i.StatusString = TorrentStatus(i.Status)
+	}
 }
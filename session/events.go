@@ -0,0 +1,104 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x0bsod/torrBot/torrent"
+)
+
+type EventType int
+
+const (
+	Added EventType = iota
+	StatusChanged
+	Completed
+	ErrorRaised
+	ErrorCleared
+	Removed
+	Stalled
+)
+
+func (e EventType) String() string {
+	_strings := []string{
+		"added",
+		"status changed",
+		"completed",
+		"error raised",
+		"error cleared",
+		"removed",
+		"stalled",
+	}
+
+	if e < Added || e > Stalled {
+		return ""
+	}
+
+	return _strings[e]
+}
+
+// Event - torrent lifecycle change between two snapshots
+// Prev is nil for Added, Torrent is the last known state for Removed
+type Event struct {
+	Type    EventType
+	Torrent *torrent.Torrent
+	Prev    *torrent.Torrent
+}
+
+// Watch poll daemon every interval and send events to returned channel
+// channel is closed when ctx is done, poll errors passed to onErr if it isn't nil
+func (p *Poller) Watch(ctx context.Context, interval time.Duration, onErr func(error)) <-chan Event {
+	ch := make(chan Event, 64)
+
+	go func() {
+		defer close(ch)
+
+		Every(ctx, interval, onErr, func() error {
+			events, err := p.poll(ctx)
+			for _, e := range events {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			return err
+		})
+	}()
+
+	return ch
+}
+
+// diff return events between previous and current state of one torrent
+func diff(prev, cur *torrent.Torrent) []Event {
+	if prev == nil {
+		return []Event{{Type: Added, Torrent: cur}}
+	}
+
+	var events []Event
+	add := func(t EventType) {
+		events = append(events, Event{Type: t, Torrent: cur, Prev: prev})
+	}
+
+	if prev.Status != cur.Status {
+		add(StatusChanged)
+	}
+	if !isComplete(prev) && isComplete(cur) {
+		add(Completed)
+	}
+	if cur.Error != 0 && (prev.Error != cur.Error || prev.ErrorString != cur.ErrorString) {
+		add(ErrorRaised)
+	}
+	if prev.Error != 0 && cur.Error == 0 {
+		add(ErrorCleared)
+	}
+	if !prev.IsStalled && cur.IsStalled {
+		add(Stalled)
+	}
+
+	return events
+}
+
+func isComplete(t *torrent.Torrent) bool {
+	return t.IsFinished || t.PercentDone >= 1
+}
@@ -0,0 +1,170 @@
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/0x0bsod/torrBot/torrent"
+)
+
+// baseFields always requested by Poller, needed for snapshot keys and events
+var baseFields = []torrent.GetField{
+	torrent.ID,
+	torrent.HashString,
+	torrent.Name,
+	torrent.Status,
+	torrent.Error,
+	torrent.ErrorString,
+	torrent.IsFinished,
+	torrent.IsStalled,
+	torrent.PercentDone,
+}
+
+// Poller - in-memory snapshot of daemon torrents
+// first Poll fetches all torrents, next ones fetch only "recently-active" and drop removed
+type Poller struct {
+	session *Session
+	fields  []torrent.GetField
+
+	mu     sync.RWMutex
+	full   bool
+	seeded bool
+	byID   map[int]*torrent.Torrent
+	byHash map[string]*torrent.Torrent
+}
+
+// NewPoller return poller for given fields, baseFields are always requested
+// fields newer than daemon rpc-version make every poll fail
+func (s *Session) NewPoller(f ...torrent.GetField) *Poller {
+	return &Poller{
+		session: s,
+		fields:  append(append([]torrent.GetField{}, baseFields...), f...),
+		byID:    map[int]*torrent.Torrent{},
+		byHash:  map[string]*torrent.Torrent{},
+	}
+}
+
+// Poll update snapshot from daemon
+func (p *Poller) Poll() error {
+	return p.PollCtx(p.session.context())
+}
+
+func (p *Poller) PollCtx(ctx context.Context) error {
+	_, err := p.poll(ctx)
+	return err
+}
+
+// poll update snapshot and return events against previous one
+// first poll only seeds the snapshot and return no events
+func (p *Poller) poll(ctx context.Context) ([]Event, error) {
+	p.mu.RLock()
+	full := p.full
+	p.mu.RUnlock()
+
+	v, _ := p.session.Version()
+	err := torrent.CheckFields(v.RPCVersion, p.fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ReqArguments{Fields: torrent.FieldList(p.fields...)}
+	if full {
+		args.IDs = SelectRecentlyActive()
+	}
+
+	res, err := p.session.WrappedCallCtx(ctx, &Request{
+		Method:    "torrent-get",
+		Arguments: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var r torrent.Torrents
+	err = ExtractArgs(res, &r)
+	if err != nil {
+		return nil, err
+	}
+	r.ResolveStatus()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var events []Event
+
+	removed := r.Removed
+	if !full {
+		// full fetch, everything missing from it is gone
+		seen := make(map[int]bool, len(r.Torrents))
+		for _, i := range r.Torrents {
+			seen[i.ID] = true
+		}
+		for ID := range p.byID {
+			if !seen[ID] {
+				removed = append(removed, ID)
+			}
+		}
+	}
+
+	for _, i := range removed {
+		if old, ok := p.byID[i]; ok {
+			delete(p.byHash, old.HashString)
+			delete(p.byID, i)
+			events = append(events, Event{Type: Removed, Torrent: old, Prev: old})
+		}
+	}
+
+	for _, i := range r.Torrents {
+		events = append(events, diff(p.byID[i.ID], i)...)
+		p.byID[i.ID] = i
+		p.byHash[i.HashString] = i
+	}
+
+	p.full = true
+	if !p.seeded {
+		p.seeded = true
+		return nil, nil
+	}
+
+	return events, nil
+}
+
+// Reset make next Poll fetch all torrents again
+func (p *Poller) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.full = false
+}
+
+// Snapshot return torrents sorted by ID
+// torrents are replaced on update, never changed in place, so they are safe to read
+func (p *Poller) Snapshot() []*torrent.Torrent {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	tmp := make([]*torrent.Torrent, 0, len(p.byID))
+	for _, i := range p.byID {
+		tmp = append(tmp, i)
+	}
+	sort.Slice(tmp, func(i, j int) bool { return tmp[i].ID < tmp[j].ID })
+
+	return tmp
+}
+
+func (p *Poller) ByID(ID int) (*torrent.Torrent, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	t, ok := p.byID[ID]
+	return t, ok
+}
+
+func (p *Poller) ByHash(hash string) (*torrent.Torrent, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	t, ok := p.byHash[hash]
+	return t, ok
+}
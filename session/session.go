@@ -1,57 +1,57 @@
 package session
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/0x0bsod/torrBot/torrent"
-	"log"
-	"net/http"
-	"time"
 )
 
 // Session - main struct
 type Session struct {
 	*Client
+	ctx context.Context
 }
 
 // NewSession return session instance with token
 func NewSession(p Parameters) (*Session, error) {
-	tr := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    30 * time.Second,
-		DisableCompression: true,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
-	}
-
-	client := Client{
-		Url:      p.Url,
-		User:     p.User,
-		Password: p.Password,
-		Debug:    p.Debug,
-		Http:     &http.Client{Transport: tr},
-	}
+	return NewWithOptions(append(p.Options(), WithHandshake())...)
+}
 
-	resp, err := client.getResponse("GET", "/", nil)
+// NewWithOptions return session instance, no network calls are made unless WithHandshake is given
+func NewWithOptions(opts ...Option) (*Session, error) {
+	c, err := New(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("error during getting token: %s", err)
+		return nil, err
 	}
 
-	client.Token = resp.Header.Get("X-Transmission-Session-Id")
+	return &Session{Client: c}, nil
+}
+
+// WithContext return copy of session, its calls are bound to ctx
+func (s *Session) WithContext(ctx context.Context) *Session {
+	return &Session{Client: s.Client, ctx: ctx}
+}
 
-	return &Session{&client}, nil
+func (s *Session) context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
 }
 
 func (s *Session) WrappedCall(r *Request) (*Response, error) {
-	data, err := s.ApiCall(r)
+	return s.WrappedCallCtx(s.context(), r)
+}
+
+func (s *Session) WrappedCallCtx(ctx context.Context, r *Request) (*Response, error) {
+	data, err := s.ApiCallCtx(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
 	if s.Debug {
-		log.Printf("[DEBUG] %s", string(data))
+		s.Logf("[DEBUG] %s", string(data))
 	}
 
 	var res Response
@@ -105,20 +105,41 @@ func (s *Session) GetAllTorrents() ([]*torrent.Torrent, error) {
 		return []*torrent.Torrent{}, err
 	}
 
-	if res.Result == "success" {
-		var r torrent.Torrents
-		err := ExtractArgs(res, &r)
-		if err != nil {
-			return []*torrent.Torrent{}, err
-		}
-		if len(r.Torrents) == 0 {
-			return r.Torrents, fmt.Errorf("no torrents")
-		}
+	var r torrent.Torrents
+	err = ExtractArgs(res, &r)
+	if err != nil {
+		return []*torrent.Torrent{}, err
+	}
+	if len(r.Torrents) == 0 {
+		return r.Torrents, fmt.Errorf("no torrents")
+	}
+
+	r.ResolveStatus()
+
+	return r.Torrents, nil
+}
+
+func (s *Session) StartTorrents(IDs *TorrentSelector) error {
+	return s.action("torrent-start", ReqArguments{IDs: IDs})
+}
+
+func (s *Session) StopTorrents(IDs *TorrentSelector) error {
+	return s.action("torrent-stop", ReqArguments{IDs: IDs})
+}
 
-		r.ResolveStatus()
+func (s *Session) RemoveTorrents(IDs *TorrentSelector, rmLocalData bool) error {
+	return s.action("torrent-remove", ReqArguments{IDs: IDs, DeleteLocalData: rmLocalData})
+}
 
-		return r.Torrents, nil
+// action - call method without result arguments [PRIVATE]
+func (s *Session) action(method string, args ReqArguments) error {
+	_, err := s.WrappedCall(&Request{
+		Method:    method,
+		Arguments: args,
+	})
+	if err != nil {
+		return err
 	}
 
-	return []*torrent.Torrent{}, fmt.Errorf("request failed")
+	return nil
 }
@@ -0,0 +1,86 @@
+package torrent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// Bitfield - pieces we have, base64 encoded on the wire
+// piece 0 is the high bit of the first byte, as in BitTorrent bitfield message
+type Bitfield []byte
+
+// Has report piece i is downloaded and verified
+func (b Bitfield) Has(i int) bool {
+	if i < 0 || i/8 >= len(b) {
+		return false
+	}
+	return b[i/8]&(0x80>>uint(i%8)) != 0
+}
+
+// Count return number of pieces we have
+func (b Bitfield) Count() int {
+	n := 0
+	for _, i := range b {
+		n += bits.OnesCount8(i)
+	}
+	return n
+}
+
+// Missing return indexes of pieces we don't have, pieceCount is Torrent.PieceCount
+func (b Bitfield) Missing(pieceCount int) []int {
+	var tmp []int
+	for i := 0; i < pieceCount; i++ {
+		if !b.Has(i) {
+			tmp = append(tmp, i)
+		}
+	}
+	return tmp
+}
+
+func (b Bitfield) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.StdEncoding.EncodeToString(b))
+}
+
+func (b *Bitfield) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("pieces: %s", err)
+	}
+
+	tmp, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("pieces: %s", err)
+	}
+
+	*b = tmp
+	return nil
+}
+
+// Flags - per-file booleans, older daemons send them as 0 and 1
+type Flags []bool
+
+func (f *Flags) UnmarshalJSON(data []byte) error {
+	var raw []interface{}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	tmp := make(Flags, len(raw))
+	for n, i := range raw {
+		switch v := i.(type) {
+		case bool:
+			tmp[n] = v
+		case float64:
+			tmp[n] = v != 0
+		default:
+			return fmt.Errorf("unexpected flag %v", i)
+		}
+	}
+
+	*f = tmp
+	return nil
+}
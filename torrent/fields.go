@@ -1,159 +1,60 @@
 package torrent
 
-type GetField int
-
-const (
-	ActivityDate GetField = iota
-	AddedDate
-	BandwidthPriority
-	Comment
-	CorruptEver
-	Creator
-	DateCreated
-	DesiredAvailable
-	DoneDate
-	DownloadDir
-	DownloadedEver
-	DownloadLimit
-	DownloadLimited
-	EditDate
-	Error
-	ErrorString
-	Eta
-	EtaIdle
-	Files
-	FileStats
-	HashString
-	HaveUnchecked
-	HaveValid
-	HonorsSessionLimits
-	ID
-	IsFinished
-	IsPrivate
-	IsStalled
-	Labels
-	LeftUntilDone
-	MagnetLink
-	ManualAnnounceTime
-	MaxConnectedPeers
-	MetadataPercentComplete
-	Name
-	PeerLimit
-	Peers
-	PeersConnected
-	PeersFrom
-	PeersGettingFromUs
-	PeersSendingToUs
-	PercentDone
-	Pieces
-	PieceCount
-	PieceSize
-	Priorities
-	QueuePosition
-	RateDownload
-	RateUpload
-	RecheckProgress
-	SecondsDownloading
-	SecondsSeeding
-	SeedIdleLimit
-	SeedIdleMode
-	SeedRatioLimit
-	SeedRatioMode
-	SizeWhenDone
-	StartDate
-	Status
-	Trackers
-	TrackerStats
-	TotalSize
-	TorrentFile
-	UploadedEver
-	UploadLimit
-	UploadLimited
-	UploadRatio
-	Wanted
-	Webseeds
-	WebseedsSendingToUs
+import (
+	"fmt"
+	"strings"
 )
 
+//go:generate go run gen_fields.go
+
+// enum, keys and Torrent struct are in fields_gen.go, edit fields.schema instead
+
 func (f GetField) String() string {
-	_strings := []string{
-		"activityDate",
-		"addedDate",
-		"bandwidthPriority",
-		"comment",
-		"corruptEver",
-		"creator",
-		"dateCreated",
-		"desiredAvailable",
-		"doneDate",
-		"downloadDir",
-		"downloadedEver",
-		"downloadLimit",
-		"downloadLimited",
-		"editDate",
-		"error",
-		"errorString",
-		"eta",
-		"etaIdle",
-		"files",
-		"fileStats",
-		"hashString",
-		"haveUnchecked",
-		"haveValid",
-		"honorsSessionLimits",
-		"id",
-		"isFinished",
-		"isPrivate",
-		"isStalled",
-		"labels",
-		"leftUntilDone",
-		"magnetLink",
-		"manualAnnounceTime",
-		"maxConnectedPeers",
-		"metadataPercentComplete",
-		"name",
-		"peer-limit",
-		"peers",
-		"peersConnected",
-		"peersFrom",
-		"peersGettingFromUs",
-		"peersSendingToUs",
-		"percentDone",
-		"pieces",
-		"pieceCount",
-		"pieceSize",
-		"priorities",
-		"queuePosition",
-		"rateDownload",
-		"rateUpload",
-		"recheckProgress",
-		"secondsDownloading",
-		"secondsSeeding",
-		"seedIdleLimit",
-		"seedIdleMode",
-		"seedRatioLimit",
-		"seedRatioMode",
-		"sizeWhenDone",
-		"startDate",
-		"status",
-		"trackers",
-		"trackerStats",
-		"totalSize",
-		"torrentFile",
-		"uploadedEver",
-		"uploadLimit",
-		"uploadLimited",
-		"uploadRatio",
-		"wanted",
-		"webseeds",
-		"webseedsSendingToUs",
+	if f < 0 || f >= fieldCount {
+		return ""
 	}
 
-	if f < ActivityDate || f > WebseedsSendingToUs {
-		return ""
+	return fieldKeys[f]
+}
+
+// Since return rpc-version which introduced the field
+func (f GetField) Since() int {
+	if f < 0 || f >= fieldCount {
+		return 0
 	}
 
-	return _strings[f]
+	return fieldVersions[f]
+}
+
+// CheckFields refuse fields newer than daemon rpc-version, 0 means not known yet and nothing is refused
+func CheckFields(rpcVersion int, f ...GetField) error {
+	if rpcVersion == 0 {
+		return nil
+	}
+
+	for _, i := range f {
+		if i.Since() > rpcVersion {
+			return fmt.Errorf("%w: field %s needs rpc-version %d, daemon has %d", ErrUnsupported, i, i.Since(), rpcVersion)
+		}
+	}
+
+	return nil
+}
+
+// JSON-RPC responses use snake_case keys, Torrent keys map them back
+func init() {
+	RegisterStruct("torrent-get", Torrents{})
+}
+
+// ParseField return field by its torrent-get key, case insensitive
+func ParseField(key string) (GetField, bool) {
+	for f := GetField(0); f < fieldCount; f++ {
+		if strings.EqualFold(fieldKeys[f], key) {
+			return f, true
+		}
+	}
+
+	return 0, false
 }
 
 func FieldList(f ...GetField) []string {
@@ -167,6 +68,17 @@ func FieldList(f ...GetField) []string {
 }
 
 //=====
+// torrent status values
+const (
+	StatusStopped = iota
+	StatusCheckWait
+	StatusCheck
+	StatusDownloadWait
+	StatusDownload
+	StatusSeedWait
+	StatusSeed
+)
+
 func TorrentStatus(ID int) string {
 	_strings := []string{
 		"Torrent is stopped",
@@ -0,0 +1,303 @@
+// Code generated by gen_fields.go from fields.schema. DO NOT EDIT.
+
+package torrent
+
+type GetField int
+
+const (
+	ActivityDate GetField = iota
+	AddedDate
+	BandwidthPriority
+	Comment
+	CorruptEver
+	Creator
+	DateCreated
+	DesiredAvailable
+	DoneDate
+	DownloadDir
+	DownloadedEver
+	DownloadLimit
+	DownloadLimited
+	EditDate
+	Error
+	ErrorString
+	Eta
+	EtaIdle
+	Files
+	FileStats
+	HashString
+	HaveUnchecked
+	HaveValid
+	HonorsSessionLimits
+	ID
+	IsFinished
+	IsPrivate
+	IsStalled
+	Labels
+	LeftUntilDone
+	MagnetLink
+	ManualAnnounceTime
+	MaxConnectedPeers
+	MetadataPercentComplete
+	Name
+	PeerLimit
+	Peers
+	PeersConnected
+	PeersFrom
+	PeersGettingFromUs
+	PeersSendingToUs
+	PercentDone
+	Pieces
+	PieceCount
+	PieceSize
+	Priorities
+	QueuePosition
+	RateDownload
+	RateUpload
+	RecheckProgress
+	SecondsDownloading
+	SecondsSeeding
+	SeedIdleLimit
+	SeedIdleMode
+	SeedRatioLimit
+	SeedRatioMode
+	SizeWhenDone
+	StartDate
+	Status
+	Trackers
+	TrackerStats
+	TotalSize
+	TorrentFile
+	UploadedEver
+	UploadLimit
+	UploadLimited
+	UploadRatio
+	Wanted
+	Webseeds
+	WebseedsSendingToUs
+
+	// number of GetField values
+	fieldCount
+)
+
+// torrent-get keys
+var fieldKeys = [fieldCount]string{
+	ActivityDate:            "activityDate",
+	AddedDate:               "addedDate",
+	BandwidthPriority:       "bandwidthPriority",
+	Comment:                 "comment",
+	CorruptEver:             "corruptEver",
+	Creator:                 "creator",
+	DateCreated:             "dateCreated",
+	DesiredAvailable:        "desiredAvailable",
+	DoneDate:                "doneDate",
+	DownloadDir:             "downloadDir",
+	DownloadedEver:          "downloadedEver",
+	DownloadLimit:           "downloadLimit",
+	DownloadLimited:         "downloadLimited",
+	EditDate:                "editDate",
+	Error:                   "error",
+	ErrorString:             "errorString",
+	Eta:                     "eta",
+	EtaIdle:                 "etaIdle",
+	Files:                   "files",
+	FileStats:               "fileStats",
+	HashString:              "hashString",
+	HaveUnchecked:           "haveUnchecked",
+	HaveValid:               "haveValid",
+	HonorsSessionLimits:     "honorsSessionLimits",
+	ID:                      "id",
+	IsFinished:              "isFinished",
+	IsPrivate:               "isPrivate",
+	IsStalled:               "isStalled",
+	Labels:                  "labels",
+	LeftUntilDone:           "leftUntilDone",
+	MagnetLink:              "magnetLink",
+	ManualAnnounceTime:      "manualAnnounceTime",
+	MaxConnectedPeers:       "maxConnectedPeers",
+	MetadataPercentComplete: "metadataPercentComplete",
+	Name:                    "name",
+	PeerLimit:               "peer-limit",
+	Peers:                   "peers",
+	PeersConnected:          "peersConnected",
+	PeersFrom:               "peersFrom",
+	PeersGettingFromUs:      "peersGettingFromUs",
+	PeersSendingToUs:        "peersSendingToUs",
+	PercentDone:             "percentDone",
+	Pieces:                  "pieces",
+	PieceCount:              "pieceCount",
+	PieceSize:               "pieceSize",
+	Priorities:              "priorities",
+	QueuePosition:           "queuePosition",
+	RateDownload:            "rateDownload",
+	RateUpload:              "rateUpload",
+	RecheckProgress:         "recheckProgress",
+	SecondsDownloading:      "secondsDownloading",
+	SecondsSeeding:          "secondsSeeding",
+	SeedIdleLimit:           "seedIdleLimit",
+	SeedIdleMode:            "seedIdleMode",
+	SeedRatioLimit:          "seedRatioLimit",
+	SeedRatioMode:           "seedRatioMode",
+	SizeWhenDone:            "sizeWhenDone",
+	StartDate:               "startDate",
+	Status:                  "status",
+	Trackers:                "trackers",
+	TrackerStats:            "trackerStats",
+	TotalSize:               "totalSize",
+	TorrentFile:             "torrentFile",
+	UploadedEver:            "uploadedEver",
+	UploadLimit:             "uploadLimit",
+	UploadLimited:           "uploadLimited",
+	UploadRatio:             "uploadRatio",
+	Wanted:                  "wanted",
+	Webseeds:                "webseeds",
+	WebseedsSendingToUs:     "webseedsSendingToUs",
+}
+
+// rpc-version which introduced the field
+var fieldVersions = [fieldCount]int{
+	ActivityDate:            1,
+	AddedDate:               1,
+	BandwidthPriority:       5,
+	Comment:                 1,
+	CorruptEver:             1,
+	Creator:                 1,
+	DateCreated:             1,
+	DesiredAvailable:        1,
+	DoneDate:                1,
+	DownloadDir:             1,
+	DownloadedEver:          1,
+	DownloadLimit:           1,
+	DownloadLimited:         5,
+	EditDate:                16,
+	Error:                   1,
+	ErrorString:             1,
+	Eta:                     1,
+	EtaIdle:                 15,
+	Files:                   1,
+	FileStats:               1,
+	HashString:              1,
+	HaveUnchecked:           1,
+	HaveValid:               1,
+	HonorsSessionLimits:     5,
+	ID:                      1,
+	IsFinished:              9,
+	IsPrivate:               1,
+	IsStalled:               14,
+	Labels:                  16,
+	LeftUntilDone:           1,
+	MagnetLink:              7,
+	ManualAnnounceTime:      1,
+	MaxConnectedPeers:       1,
+	MetadataPercentComplete: 7,
+	Name:                    1,
+	PeerLimit:               1,
+	Peers:                   1,
+	PeersConnected:          1,
+	PeersFrom:               1,
+	PeersGettingFromUs:      1,
+	PeersSendingToUs:        1,
+	PercentDone:             1,
+	Pieces:                  5,
+	PieceCount:              1,
+	PieceSize:               1,
+	Priorities:              1,
+	QueuePosition:           14,
+	RateDownload:            1,
+	RateUpload:              1,
+	RecheckProgress:         1,
+	SecondsDownloading:      1,
+	SecondsSeeding:          1,
+	SeedIdleLimit:           10,
+	SeedIdleMode:            10,
+	SeedRatioLimit:          5,
+	SeedRatioMode:           5,
+	SizeWhenDone:            1,
+	StartDate:               1,
+	Status:                  1,
+	Trackers:                1,
+	TrackerStats:            1,
+	TotalSize:               1,
+	TorrentFile:             5,
+	UploadedEver:            1,
+	UploadLimit:             1,
+	UploadLimited:           5,
+	UploadRatio:             1,
+	Wanted:                  1,
+	Webseeds:                1,
+	WebseedsSendingToUs:     1,
+}
+
+type Torrent struct {
+	ActivityDate            int               `json:"activityDate,omitempty"`
+	AddedDate               int               `json:"addedDate,omitempty"`
+	BandwidthPriority       int               `json:"bandwidthPriority,omitempty"`
+	Comment                 string            `json:"comment,omitempty"`
+	CorruptEver             int               `json:"corruptEver,omitempty"`
+	Creator                 string            `json:"creator,omitempty"`
+	DateCreated             int               `json:"dateCreated,omitempty"`
+	DesiredAvailable        int               `json:"desiredAvailable,omitempty"`
+	DoneDate                int               `json:"doneDate,omitempty"`
+	DownloadDir             string            `json:"downloadDir,omitempty"`
+	DownloadedEver          int               `json:"downloadedEver,omitempty"`
+	DownloadLimit           int               `json:"downloadLimit,omitempty"`
+	DownloadLimited         bool              `json:"downloadLimited,omitempty"`
+	EditDate                int               `json:"editDate,omitempty"`
+	Error                   int               `json:"error,omitempty"`
+	ErrorString             string            `json:"errorString,omitempty"`
+	Eta                     int               `json:"eta,omitempty"`
+	EtaIdle                 int               `json:"etaIdle,omitempty"`
+	Files                   []ArgFiles        `json:"files,omitempty"`
+	FileStats               []ArgFileStats    `json:"fileStats,omitempty"`
+	HashString              string            `json:"hashString,omitempty"`
+	HaveUnchecked           int               `json:"haveUnchecked,omitempty"`
+	HaveValid               int               `json:"haveValid,omitempty"`
+	HonorsSessionLimits     bool              `json:"honorsSessionLimits,omitempty"`
+	ID                      int               `json:"id,omitempty"`
+	IsFinished              bool              `json:"isFinished,omitempty"`
+	IsPrivate               bool              `json:"isPrivate,omitempty"`
+	IsStalled               bool              `json:"isStalled,omitempty"`
+	Labels                  []string          `json:"labels,omitempty"`
+	LeftUntilDone           int               `json:"leftUntilDone,omitempty"`
+	MagnetLink              string            `json:"magnetLink,omitempty"`
+	ManualAnnounceTime      int               `json:"manualAnnounceTime,omitempty"`
+	MaxConnectedPeers       int               `json:"maxConnectedPeers,omitempty"`
+	MetadataPercentComplete float64           `json:"metadataPercentComplete,omitempty"`
+	Name                    string            `json:"name,omitempty"`
+	PeerLimit               int               `json:"peer-limit,omitempty"`
+	Peers                   []ArgPeers        `json:"peers,omitempty"`
+	PeersConnected          int               `json:"peersConnected,omitempty"`
+	PeersFrom               *ArgPeersFrom     `json:"peersFrom,omitempty"`
+	PeersGettingFromUs      int               `json:"peersGettingFromUs,omitempty"`
+	PeersSendingToUs        int               `json:"peersSendingToUs,omitempty"`
+	PercentDone             float64           `json:"percentDone,omitempty"`
+	Pieces                  Bitfield          `json:"pieces,omitempty"`
+	PieceCount              int               `json:"pieceCount,omitempty"`
+	PieceSize               int               `json:"pieceSize,omitempty"`
+	Priorities              []int             `json:"priorities,omitempty"`
+	QueuePosition           int               `json:"queuePosition,omitempty"`
+	RateDownload            int               `json:"rateDownload,omitempty"`
+	RateUpload              int               `json:"rateUpload,omitempty"`
+	RecheckProgress         float64           `json:"recheckProgress,omitempty"`
+	SecondsDownloading      int               `json:"secondsDownloading,omitempty"`
+	SecondsSeeding          int               `json:"secondsSeeding,omitempty"`
+	SeedIdleLimit           int               `json:"seedIdleLimit,omitempty"`
+	SeedIdleMode            int               `json:"seedIdleMode,omitempty"`
+	SeedRatioLimit          float64           `json:"seedRatioLimit,omitempty"`
+	SeedRatioMode           int               `json:"seedRatioMode,omitempty"`
+	SizeWhenDone            int               `json:"sizeWhenDone,omitempty"`
+	StartDate               int               `json:"startDate,omitempty"`
+	Status                  int               `json:"status,omitempty"`
+	StatusString            string            `json:"status_string,omitempty"`
+	Trackers                []ArgTrackers     `json:"trackers,omitempty"`
+	TrackerStats            []ArgTrackerStats `json:"trackerStats,omitempty"`
+	TotalSize               int               `json:"totalSize,omitempty"`
+	TorrentFile             string            `json:"torrentFile,omitempty"`
+	UploadedEver            int               `json:"uploadedEver,omitempty"`
+	UploadLimit             int               `json:"uploadLimit,omitempty"`
+	UploadLimited           bool              `json:"uploadLimited,omitempty"`
+	UploadRatio             float64           `json:"uploadRatio,omitempty"`
+	Wanted                  Flags             `json:"wanted,omitempty"`
+	Webseeds                []string          `json:"webseeds,omitempty"`
+	WebseedsSendingToUs     int               `json:"webseedsSendingToUs,omitempty"`
+}
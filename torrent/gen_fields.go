@@ -0,0 +1,49 @@
+//go:build ignore
+// +build ignore
+
+// gen_fields generates fields_gen.go from fields.schema
+// with -check it only reports fields_gen.go is out of date
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/0x0bsod/torrBot/torrent/internal/fieldgen"
+)
+
+func main() {
+	check := flag.Bool("check", false, "fail if "+fieldgen.OutFile+" differs from generated code")
+	flag.Parse()
+
+	fields, err := fieldgen.ParseFile(fieldgen.SchemaFile)
+	if err != nil {
+		log.Fatalf("%s: %s", fieldgen.SchemaFile, err)
+	}
+
+	src, err := fieldgen.Generate(fields)
+	if err != nil {
+		log.Fatalf("error during generation: %s", err)
+	}
+
+	if *check {
+		cur, err := ioutil.ReadFile(fieldgen.OutFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !bytes.Equal(cur, src) {
+			fmt.Fprintf(os.Stderr, "%s is out of date with %s, run go generate ./torrent\n", fieldgen.OutFile, fieldgen.SchemaFile)
+			os.Exit(1)
+		}
+		return
+	}
+
+	err = ioutil.WriteFile(fieldgen.OutFile, src, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package fieldgen generates torrent/fields_gen.go from torrent/fields.schema
+package fieldgen
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	SchemaFile = "fields.schema"
+	OutFile    = "fields_gen.go"
+)
+
+type Field struct {
+	Key    string
+	GoName string
+	Type   string
+	Since  int // 0 for struct only fields
+}
+
+// ParseFile parse schema at path
+func ParseFile(path string) ([]Field, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse read schema lines "key type since", since is "-" for struct only fields, # starts a comment
+func Parse(r io.Reader) ([]Field, error) {
+	var fields []Field
+	keys := map[string]bool{}
+	names := map[string]bool{}
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		cols := strings.Fields(text)
+		if len(cols) == 0 {
+			continue
+		}
+		if len(cols) != 3 {
+			return nil, fmt.Errorf("line %d: want key, type and since, got %q", line, text)
+		}
+
+		i := Field{Key: cols[0], GoName: goName(cols[0]), Type: cols[1]}
+		if cols[2] != "-" {
+			var err error
+			i.Since, err = strconv.Atoi(cols[2])
+			if err != nil || i.Since < 1 {
+				return nil, fmt.Errorf("line %d: bad rpc-version %q", line, cols[2])
+			}
+		}
+
+		if keys[i.Key] || names[i.GoName] {
+			return nil, fmt.Errorf("line %d: duplicate field %s", line, i.Key)
+		}
+		keys[i.Key] = true
+		names[i.GoName] = true
+
+		fields = append(fields, i)
+	}
+
+	return fields, sc.Err()
+}
+
+// goName convert "hashString", "peer-limit" and "id" to "HashString", "PeerLimit" and "ID"
+func goName(key string) string {
+	parts := strings.FieldsFunc(key, func(r rune) bool { return r == '-' || r == '_' })
+	for n, i := range parts {
+		if i == "id" {
+			parts[n] = "ID"
+			continue
+		}
+		parts[n] = strings.ToUpper(i[:1]) + i[1:]
+	}
+
+	return strings.Join(parts, "")
+}
+
+// Generate return gofmt-ed fields_gen.go source
+func Generate(fields []Field) ([]byte, error) {
+	var buf bytes.Buffer
+	p := func(format string, v ...interface{}) { fmt.Fprintf(&buf, format, v...) }
+
+	p("// Code generated by gen_fields.go from %s. DO NOT EDIT.\n\n", SchemaFile)
+	p("package torrent\n\n")
+
+	p("type GetField int\n\n")
+	p("const (\n")
+	first := true
+	for _, i := range fields {
+		if i.Since == 0 {
+			continue
+		}
+		if first {
+			p("%s GetField = iota\n", i.GoName)
+			first = false
+			continue
+		}
+		p("%s\n", i.GoName)
+	}
+	p("\n// number of GetField values\nfieldCount\n")
+	p(")\n\n")
+
+	p("// torrent-get keys\n")
+	p("var fieldKeys = [fieldCount]string{\n")
+	for _, i := range fields {
+		if i.Since > 0 {
+			p("%s: %q,\n", i.GoName, i.Key)
+		}
+	}
+	p("}\n\n")
+
+	p("// rpc-version which introduced the field\n")
+	p("var fieldVersions = [fieldCount]int{\n")
+	for _, i := range fields {
+		if i.Since > 0 {
+			p("%s: %d,\n", i.GoName, i.Since)
+		}
+	}
+	p("}\n\n")
+
+	p("type Torrent struct {\n")
+	for _, i := range fields {
+		p("%s %s `json:\"%s,omitempty\"`\n", i.GoName, i.Type, i.Key)
+	}
+	p("}\n")
+
+	return format.Source(buf.Bytes())
+}
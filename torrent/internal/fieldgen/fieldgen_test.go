@@ -0,0 +1,32 @@
+package fieldgen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// torrent package dir, relative to this one
+var torrentDir = filepath.Join("..", "..")
+
+func TestFieldsGenUpToDate(t *testing.T) {
+	fields, err := ParseFile(filepath.Join(torrentDir, SchemaFile))
+	if err != nil {
+		t.Fatalf("%s: %s", SchemaFile, err)
+	}
+
+	want, err := Generate(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(filepath.Join(torrentDir, OutFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s is out of date with %s, run go generate ./torrent", OutFile, SchemaFile)
+	}
+}
@@ -1,45 +1,21 @@
 package torrent
 
 import (
-	"fmt"
 	"github.com/0x0bsod/torrBot/session"
 )
 
 type Torrents struct {
 	Session  *session.Session `json:"-"`
 	Torrents []*Torrent       `json:"torrents"`
-}
-
-type Torrent struct {
-	ActivityDate      int            `json:"activityDate,omitempty"`
-	AddedDate         int            `json:"addedDate,omitempty"`
-	BandwidthPriority int            `json:"bandwidthPriority,omitempty"`
-	Comment           string         `json:"comment,omitempty"`
-	Error             int            `json:"error,omitempty"`
-	ErrorString       string         `json:"errorString,omitempty"`
-	Eta               int            `json:"eta,omitempty"`
-	ID                int            `json:"id,omitempty"`
-	IsFinished        bool           `json:"isFinished,omitempty"`
-	LeftUntilDone     int            `json:"leftUntilDone,omitempty"`
-	Name              string         `json:"name,omitempty"`
-	PercentDone       float64        `json:"percentDone,omitempty"`
-	SizeWhenDone      int            `json:"sizeWhenDone,omitempty"`
-	StartDate         int            `json:"startDate,omitempty"`
-	Status            int            `json:"status,omitempty"`
-	StatusString      string         `json:"status_string,omitempty"`
-	TotalSize         int            `json:"totalSize,omitempty"`
-	UploadRatio       float64        `json:"uploadRatio,omitempty"`
-	Peers             []ArgPeers     `json:"peers,omitempty"`
-	RateDownload      int            `json:"rateDownload,omitempty"`
-	RateUpload        int            `json:"rateUpload,omitempty"`
-	Files             []ArgFiles     `json:"files,omitempty"`
-	FileStats         []ArgFileStats `json:"fileStats,omitempty"`
+	Removed  []int            `json:"removed,omitempty"`
 }
 
 type ArgFiles struct {
 	BytesCompleted int    `json:"bytesCompleted"`
 	Length         int    `json:"length"`
 	Name           string `json:"name"`
+	BeginPiece     int    `json:"begin_piece,omitempty"` // rpc-version 17
+	EndPiece       int    `json:"end_piece,omitempty"`   // rpc-version 17
 }
 
 type ArgFileStats struct {
@@ -67,67 +43,145 @@ type ArgPeers struct {
 	RateToPeer         int     `json:"rateToPeer"`
 }
 
+type ArgPeersFrom struct {
+	FromCache    int `json:"fromCache"`
+	FromDht      int `json:"fromDht"`
+	FromIncoming int `json:"fromIncoming"`
+	FromLpd      int `json:"fromLpd"`
+	FromLtep     int `json:"fromLtep"`
+	FromPex      int `json:"fromPex"`
+	FromTracker  int `json:"fromTracker"`
+}
+
+type ArgTrackers struct {
+	Announce string `json:"announce"`
+	ID       int    `json:"id"`
+	Scrape   string `json:"scrape"`
+	Sitename string `json:"sitename,omitempty"` // rpc-version 17
+	Tier     int    `json:"tier"`
+}
+
+// tracker announce and scrape states
+const (
+	TrackerInactive = iota
+	TrackerWaiting
+	TrackerQueued
+	TrackerActive
+)
+
+type ArgTrackerStats struct {
+	Announce              string `json:"announce"`
+	AnnounceState         int    `json:"announceState"`
+	DownloadCount         int    `json:"downloadCount"`
+	HasAnnounced          bool   `json:"hasAnnounced"`
+	HasScraped            bool   `json:"hasScraped"`
+	Host                  string `json:"host"`
+	ID                    int    `json:"id"`
+	IsBackup              bool   `json:"isBackup"`
+	LastAnnouncePeerCount int    `json:"lastAnnouncePeerCount"`
+	LastAnnounceResult    string `json:"lastAnnounceResult"`
+	LastAnnounceStartTime int    `json:"lastAnnounceStartTime"`
+	LastAnnounceSucceeded bool   `json:"lastAnnounceSucceeded"`
+	LastAnnounceTime      int    `json:"lastAnnounceTime"`
+	LastAnnounceTimedOut  bool   `json:"lastAnnounceTimedOut"`
+	LastScrapeResult      string `json:"lastScrapeResult"`
+	LastScrapeStartTime   int    `json:"lastScrapeStartTime"`
+	LastScrapeSucceeded   bool   `json:"lastScrapeSucceeded"`
+	LastScrapeTime        int    `json:"lastScrapeTime"`
+	LastScrapeTimedOut    bool   `json:"lastScrapeTimedOut"`
+	LeecherCount          int    `json:"leecherCount"`
+	NextAnnounceTime      int    `json:"nextAnnounceTime"`
+	NextScrapeTime        int    `json:"nextScrapeTime"`
+	Scrape                string `json:"scrape"`
+	ScrapeState           int    `json:"scrapeState"`
+	SeederCount           int    `json:"seederCount"`
+	Sitename              string `json:"sitename,omitempty"` // rpc-version 17
+	Tier                  int    `json:"tier"`
+}
+
 //
 
-func (t *Torrents) Verify(ID int) error {
-	res, err := t.Session.ExtractArgs(&Request{
+// Verify verify local data of selected torrents
+func (t *Torrents) Verify(IDs *TorrentSelector) error {
+	_, err := t.Session.WrappedCall(&Request{
 		Method: "torrent-verify",
 		Arguments: ReqArguments{
-			IDs: []int{ID},
+			IDs: IDs,
 		},
 	})
 	if err != nil {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
-	}
-
-	return fmt.Errorf("request failed")
+	return nil
 }
 
-func (t *Torrent) Start(ID int) error {
-	res, err := t.makeCall(&Request{
+// Start start selected torrents
+func (t *Torrent) Start(IDs *TorrentSelector) error {
+	_, err := t.makeCall(&Request{
 		Method: "torrent-start",
 		Arguments: ReqArguments{
-			IDs: []int{ID},
+			IDs: IDs,
 		},
 	})
 	if err != nil {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
+	return nil
+}
+
+// StartNow start selected torrents bypassing the queue
+func (t *Torrent) StartNow(IDs *TorrentSelector) error {
+	_, err := t.makeCall(&Request{
+		Method: "torrent-start-now",
+		Arguments: ReqArguments{
+			IDs: IDs,
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("request failed")
+	return nil
 }
 
-func (t *Torrent) Stop(ID int) error {
-	res, err := t.makeCall(&Request{
+// Stop stop selected torrents
+func (t *Torrent) Stop(IDs *TorrentSelector) error {
+	_, err := t.makeCall(&Request{
 		Method: "torrent-stop",
 		Arguments: ReqArguments{
-			IDs: []int{ID},
+			IDs: IDs,
 		},
 	})
 	if err != nil {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
+	return nil
+}
+
+// Reannounce ask trackers for more peers for selected torrents
+func (t *Torrent) Reannounce(IDs *TorrentSelector) error {
+	_, err := t.makeCall(&Request{
+		Method: "torrent-reannounce",
+		Arguments: ReqArguments{
+			IDs: IDs,
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	return fmt.Errorf("request failed")
+	return nil
 }
 
-func (t *Torrent) Remove(ID int, rmLocalData bool) error {
-	res, err := t.makeCall(&Request{
+// Remove remove selected torrents, local data removed if rmLocalData
+func (t *Torrent) Remove(IDs *TorrentSelector, rmLocalData bool) error {
+	_, err := t.makeCall(&Request{
 		Method: "torrent-remove",
 		Arguments: ReqArguments{
-			IDs:             []int{ID},
+			IDs:             IDs,
 			DeleteLocalData: rmLocalData,
 		},
 	})
@@ -135,11 +189,7 @@ func (t *Torrent) Remove(ID int, rmLocalData bool) error {
 		return err
 	}
 
-	if res.Result == "success" {
-		return nil
-	}
-
-	return fmt.Errorf("request failed")
+	return nil
 }
 
 func (t *Torrents) ResolveStatus() {